@@ -81,6 +81,39 @@ func (b *Batch) Len() int {
 	return len(b.QueuedQueries)
 }
 
+// BatchError identifies the query in a batch that failed.
+type BatchError struct {
+	Index int    // Index of the failed query. For a Batch this is its index in Batch.QueuedQueries.
+	SQL   string // SQL of the failed query.
+	Err   error  // Error returned for the failed query.
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch query %d failed: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ErrBatchQuerySkipped is wrapped by the error BatchResults returns for each query after a query in the batch fails.
+// Such queries are not executed by the server. The error also wraps the *BatchError that the failed query itself and
+// Close return.
+var ErrBatchQuerySkipped = errors.New("skipped due to earlier batch error")
+
+func newBatchQuerySkippedError(failed *BatchError) error {
+	return fmt.Errorf("%w: %w", ErrBatchQuerySkipped, failed)
+}
+
+// newBatchError returns a *BatchError for err of the query at idx. err is returned as is if it already is a
+// *BatchError.
+func newBatchError(idx int, sql string, err error) *BatchError {
+	if batchErr, ok := err.(*BatchError); ok {
+		return batchErr
+	}
+	return &BatchError{Index: idx, SQL: sql, Err: err}
+}
+
 type BatchResults interface {
 	// Exec reads the results from the next query in the batch as if the query has been sent with Conn.Exec. Prefer
 	// calling Exec on the QueuedQuery.
@@ -108,18 +141,29 @@ type BatchResults interface {
 }
 
 type batchResults struct {
-	ctx       context.Context
-	conn      *Conn
-	mrr       *pgconn.MultiResultReader
-	err       error
-	b         *Batch
-	qqIdx     int
-	closed    bool
-	endTraced bool
+	ctx         context.Context
+	conn        *Conn
+	mrr         *pgconn.MultiResultReader
+	err         error
+	b           *Batch
+	qqIdx       int
+	closed      bool
+	endTraced   bool
+	lastRows    *baseRows
+	lastRowsIdx int
+	failed      *BatchError
+	cancelCtx   context.CancelFunc // releases the context created for DefaultQueryTimeout
 }
 
 // Exec reads the results from the next query in the batch as if the query has been sent with Exec.
 func (br *batchResults) Exec() (pgconn.CommandTag, error) {
+	idx := br.qqIdx
+	if err := br.skippedQueryError(); err != nil {
+		query, arguments, _ := br.nextQueryAndArgs()
+		br.traceSkippedQuery(query, arguments, err)
+		return pgconn.CommandTag{}, err
+	}
+
 	if br.err != nil {
 		return pgconn.CommandTag{}, br.err
 	}
@@ -127,12 +171,15 @@ func (br *batchResults) Exec() (pgconn.CommandTag, error) {
 		return pgconn.CommandTag{}, fmt.Errorf("batch already closed")
 	}
 
-	query, arguments, _ := br.nextQueryAndArgs()
+	query, arguments, ok := br.nextQueryAndArgs()
 
 	if !br.mrr.NextResult() {
 		err := br.mrr.Close()
 		if err == nil {
 			err = errors.New("no more results in batch")
+		} else if ok {
+			br.failed = newBatchError(idx, query, err)
+			err = br.failed
 		}
 		if br.conn.batchTracer != nil {
 			br.conn.batchTracer.TraceBatchQuery(br.ctx, br.conn, TraceBatchQueryData{
@@ -146,7 +193,8 @@ func (br *batchResults) Exec() (pgconn.CommandTag, error) {
 
 	commandTag, err := br.mrr.ResultReader().Close()
 	if err != nil {
-		br.err = err
+		br.failed = newBatchError(idx, query, err)
+		br.err = br.failed
 		br.mrr.Close()
 	}
 
@@ -164,11 +212,19 @@ func (br *batchResults) Exec() (pgconn.CommandTag, error) {
 
 // Query reads the results from the next query in the batch as if the query has been sent with Query.
 func (br *batchResults) Query() (Rows, error) {
+	idx := br.qqIdx
+	skippedErr := br.skippedQueryError()
+
 	query, arguments, ok := br.nextQueryAndArgs()
 	if !ok {
 		query = "batch query"
 	}
 
+	if skippedErr != nil {
+		br.traceSkippedQuery(query, arguments, skippedErr)
+		return &baseRows{err: skippedErr, closed: true}, skippedErr
+	}
+
 	if br.err != nil {
 		return &baseRows{err: br.err, closed: true}, br.err
 	}
@@ -180,11 +236,16 @@ func (br *batchResults) Query() (Rows, error) {
 
 	rows := br.conn.getRows(br.ctx, query, arguments)
 	rows.batchTracer = br.conn.batchTracer
+	rows.inBatch = ok
+	rows.batchIdx = idx
 
 	if !br.mrr.NextResult() {
 		rows.err = br.mrr.Close()
 		if rows.err == nil {
 			rows.err = errors.New("no more results in batch")
+		} else if ok {
+			br.failed = newBatchError(idx, query, rows.err)
+			rows.err = br.failed
 		}
 		rows.closed = true
 
@@ -200,6 +261,8 @@ func (br *batchResults) Query() (Rows, error) {
 	}
 
 	rows.resultReader = br.mrr.ResultReader()
+	br.lastRows = rows
+	br.lastRowsIdx = idx
 	return rows, nil
 }
 
@@ -220,6 +283,10 @@ func (br *batchResults) Close() error {
 			}
 			br.endTraced = true
 		}
+		if br.cancelCtx != nil {
+			br.cancelCtx()
+			br.cancelCtx = nil
+		}
 	}()
 
 	if br.err != nil {
@@ -235,6 +302,9 @@ func (br *batchResults) Close() error {
 		if br.b.QueuedQueries[br.qqIdx].Fn != nil {
 			err := br.b.QueuedQueries[br.qqIdx].Fn(br)
 			if err != nil {
+				if br.failed != nil && errors.Is(err, ErrBatchQuerySkipped) {
+					err = br.failed
+				}
 				br.err = err
 			}
 		} else {
@@ -248,6 +318,9 @@ func (br *batchResults) Close() error {
 	if br.err == nil {
 		br.err = err
 	}
+	if br.err == nil && br.failed != nil {
+		br.err = br.failed
+	}
 
 	return br.err
 }
@@ -256,6 +329,39 @@ func (br *batchResults) earlyError() error {
 	return br.err
 }
 
+// skippedQueryError returns the error for the next query if an earlier query in the batch failed. Otherwise it returns
+// nil.
+func (br *batchResults) skippedQueryError() error {
+	if br.closed {
+		return nil
+	}
+
+	if br.failed == nil && br.lastRows != nil {
+		// The error of a query read with Query is only known once its rows are closed. The rows would be closed by reading
+		// the next result anyway.
+		br.lastRows.Close()
+		if br.lastRows.err != nil {
+			br.failed = newBatchError(br.lastRowsIdx, br.lastRows.sql, br.lastRows.err)
+		}
+	}
+
+	if br.failed == nil {
+		return nil
+	}
+
+	return newBatchQuerySkippedError(br.failed)
+}
+
+func (br *batchResults) traceSkippedQuery(query string, args []any, err error) {
+	if br.conn.batchTracer != nil {
+		br.conn.batchTracer.TraceBatchQuery(br.ctx, br.conn, TraceBatchQueryData{
+			SQL:  query,
+			Args: args,
+			Err:  err,
+		})
+	}
+}
+
 func (br *batchResults) nextQueryAndArgs() (query string, args []any, ok bool) {
 	if br.b != nil && br.qqIdx < len(br.b.QueuedQueries) {
 		bi := br.b.QueuedQueries[br.qqIdx]
@@ -268,19 +374,29 @@ func (br *batchResults) nextQueryAndArgs() (query string, args []any, ok bool) {
 }
 
 type pipelineBatchResults struct {
-	ctx       context.Context
-	conn      *Conn
-	pipeline  *pgconn.Pipeline
-	lastRows  *baseRows
-	err       error
-	b         *Batch
-	qqIdx     int
-	closed    bool
-	endTraced bool
+	ctx         context.Context
+	conn        *Conn
+	pipeline    *pgconn.Pipeline
+	lastRows    *baseRows
+	lastRowsIdx int
+	err         error
+	b           *Batch
+	qqIdx       int
+	closed      bool
+	endTraced   bool
+	failed      *BatchError
+	cancelCtx   context.CancelFunc // releases the context created for DefaultQueryTimeout
 }
 
 // Exec reads the results from the next query in the batch as if the query has been sent with Exec.
 func (br *pipelineBatchResults) Exec() (pgconn.CommandTag, error) {
+	idx := br.qqIdx
+	if err := br.skippedQueryError(); err != nil {
+		query, arguments, _ := br.nextQueryAndArgs()
+		br.traceSkippedQuery(query, arguments, err)
+		return pgconn.CommandTag{}, err
+	}
+
 	if br.err != nil {
 		return pgconn.CommandTag{}, br.err
 	}
@@ -298,13 +414,17 @@ func (br *pipelineBatchResults) Exec() (pgconn.CommandTag, error) {
 
 	results, err := br.pipeline.GetResults()
 	if err != nil {
-		br.err = err
+		br.err = newBatchError(idx, query, err)
 		return pgconn.CommandTag{}, br.err
 	}
 	var commandTag pgconn.CommandTag
 	switch results := results.(type) {
 	case *pgconn.ResultReader:
-		commandTag, br.err = results.Close()
+		commandTag, err = results.Close()
+		if err != nil {
+			br.failed = newBatchError(idx, query, err)
+			br.err = br.failed
+		}
 	default:
 		return pgconn.CommandTag{}, fmt.Errorf("unexpected pipeline result: %T", results)
 	}
@@ -323,6 +443,13 @@ func (br *pipelineBatchResults) Exec() (pgconn.CommandTag, error) {
 
 // Query reads the results from the next query in the batch as if the query has been sent with Query.
 func (br *pipelineBatchResults) Query() (Rows, error) {
+	idx := br.qqIdx
+	if err := br.skippedQueryError(); err != nil {
+		query, arguments, _ := br.nextQueryAndArgs()
+		br.traceSkippedQuery(query, arguments, err)
+		return &baseRows{err: err, closed: true}, err
+	}
+
 	if br.err != nil {
 		return &baseRows{err: br.err, closed: true}, br.err
 	}
@@ -344,19 +471,22 @@ func (br *pipelineBatchResults) Query() (Rows, error) {
 
 	rows := br.conn.getRows(br.ctx, query, arguments)
 	rows.batchTracer = br.conn.batchTracer
+	rows.inBatch = true
+	rows.batchIdx = idx
 	br.lastRows = rows
+	br.lastRowsIdx = idx
 
 	results, err := br.pipeline.GetResults()
 	if err != nil {
-		br.err = err
-		rows.err = err
+		br.err = newBatchError(idx, query, err)
+		rows.err = br.err
 		rows.closed = true
 
 		if br.conn.batchTracer != nil {
 			br.conn.batchTracer.TraceBatchQuery(br.ctx, br.conn, TraceBatchQueryData{
 				SQL:  query,
 				Args: arguments,
-				Err:  err,
+				Err:  rows.err,
 			})
 		}
 	} else {
@@ -391,6 +521,10 @@ func (br *pipelineBatchResults) Close() error {
 			}
 			br.endTraced = true
 		}
+		if br.cancelCtx != nil {
+			br.cancelCtx()
+			br.cancelCtx = nil
+		}
 	}()
 
 	if br.err == nil && br.lastRows != nil && br.lastRows.err != nil {
@@ -407,6 +541,9 @@ func (br *pipelineBatchResults) Close() error {
 		if br.b.QueuedQueries[br.qqIdx].Fn != nil {
 			err := br.b.QueuedQueries[br.qqIdx].Fn(br)
 			if err != nil {
+				if br.failed != nil && errors.Is(err, ErrBatchQuerySkipped) {
+					err = br.failed
+				}
 				br.err = err
 			}
 		} else {
@@ -420,6 +557,9 @@ func (br *pipelineBatchResults) Close() error {
 	if br.err == nil {
 		br.err = err
 	}
+	if br.err == nil && br.failed != nil {
+		br.err = br.failed
+	}
 
 	return br.err
 }
@@ -428,6 +568,39 @@ func (br *pipelineBatchResults) earlyError() error {
 	return br.err
 }
 
+// skippedQueryError returns the error for the next query if an earlier query in the batch failed. Otherwise it returns
+// nil.
+func (br *pipelineBatchResults) skippedQueryError() error {
+	if br.closed {
+		return nil
+	}
+
+	if br.failed == nil && br.lastRows != nil {
+		// The error of a query read with Query is only known once its rows are closed. The rows would be closed by reading
+		// the next result anyway.
+		br.lastRows.Close()
+		if br.lastRows.err != nil {
+			br.failed = newBatchError(br.lastRowsIdx, br.lastRows.sql, br.lastRows.err)
+		}
+	}
+
+	if br.failed == nil {
+		return nil
+	}
+
+	return newBatchQuerySkippedError(br.failed)
+}
+
+func (br *pipelineBatchResults) traceSkippedQuery(query string, args []any, err error) {
+	if br.conn.batchTracer != nil {
+		br.conn.batchTracer.TraceBatchQuery(br.ctx, br.conn, TraceBatchQueryData{
+			SQL:  query,
+			Args: args,
+			Err:  err,
+		})
+	}
+}
+
 func (br *pipelineBatchResults) nextQueryAndArgs() (query string, args []any, err error) {
 	if br.b == nil {
 		return "", nil, errors.New("no reference to batch")
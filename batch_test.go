@@ -572,12 +572,13 @@ func TestConnSendBatchQueryError(t *testing.T) {
 			}
 		}
 
-		if pgErr, ok := rows.Err().(*pgconn.PgError); !(ok && pgErr.Code == "22012") {
+		var pgErr *pgconn.PgError
+		if !(errors.As(rows.Err(), &pgErr) && pgErr.Code == "22012") {
 			t.Errorf("rows.Err() => %v, want error code %v", rows.Err(), 22012)
 		}
 
 		err = br.Close()
-		if pgErr, ok := err.(*pgconn.PgError); !(ok && pgErr.Code == "22012") {
+		if !(errors.As(err, &pgErr) && pgErr.Code == "22012") {
 			t.Errorf("br.Close() => %v, want error code %v", err, 22012)
 		}
 
@@ -599,7 +600,8 @@ func TestConnSendBatchQuerySyntaxError(t *testing.T) {
 
 		var n int32
 		err := br.QueryRow().Scan(&n)
-		if pgErr, ok := err.(*pgconn.PgError); !(ok && pgErr.Code == "42601") {
+		var pgErr *pgconn.PgError
+		if !(errors.As(err, &pgErr) && pgErr.Code == "42601") {
 			t.Errorf("rows.Err() => %v, want error code %v", err, 42601)
 		}
 
@@ -891,7 +893,8 @@ func TestConnBeginBatchDeferredError(t *testing.T) {
 			t.Fatal("expected error 23505 but got none")
 		}
 
-		if err, ok := err.(*pgconn.PgError); !ok || err.Code != "23505" {
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
 			t.Fatalf("expected error 23505, got %v", err)
 		}
 
@@ -1024,7 +1027,9 @@ func TestConnSendBatchErrorDoesNotLeaveOrphanedPreparedStatement(t *testing.T) {
 		batch.Queue("select col1 from foo")
 		batch.Queue("select col1 from baz")
 		err := conn.SendBatch(ctx, batch).Close()
-		require.EqualError(t, err, `ERROR: relation "baz" does not exist (SQLSTATE 42P01)`)
+		var pgErr *pgconn.PgError
+		require.ErrorAs(t, err, &pgErr)
+		require.Equal(t, "42P01", pgErr.Code)
 
 		mustExec(t, conn, `create temporary table baz(col1 text primary key);`)
 
@@ -1096,3 +1101,85 @@ func ExampleConn_SendBatch() {
 	// 3
 	// 5
 }
+
+func TestConnSendBatchErrorAttribution(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pgxtest.RunWithQueryExecModes(ctx, t, defaultConnTestRunner, nil, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		batch := &pgx.Batch{}
+		batch.Queue("select 1")
+		batch.Queue("select 1/0")
+		batch.Queue("select 2")
+		batch.Queue("select 3")
+
+		br := conn.SendBatch(ctx, batch)
+
+		_, err := br.Exec()
+		require.NoError(t, err)
+
+		_, failedErr := br.Exec()
+		var failedBatchErr *pgx.BatchError
+		require.ErrorAs(t, failedErr, &failedBatchErr)
+		require.Equal(t, 1, failedBatchErr.Index)
+		require.Equal(t, "select 1/0", failedBatchErr.SQL)
+		var pgErr *pgconn.PgError
+		require.ErrorAs(t, failedErr, &pgErr)
+		require.Equal(t, "22012", pgErr.Code)
+
+		for i := 0; i < 2; i++ {
+			rows, err := br.Query()
+			require.ErrorIs(t, err, pgx.ErrBatchQuerySkipped)
+			require.ErrorIs(t, rows.Err(), pgx.ErrBatchQuerySkipped)
+
+			var batchErr *pgx.BatchError
+			require.ErrorAs(t, err, &batchErr)
+			require.Equal(t, 1, batchErr.Index)
+			require.Equal(t, failedErr, batchErr)
+		}
+
+		err = br.Close()
+		require.Equal(t, failedErr, err)
+
+		ensureConnValid(t, conn)
+	})
+}
+
+func TestConnSendBatchErrorAttributionAfterQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pgxtest.RunWithQueryExecModes(ctx, t, defaultConnTestRunner, nil, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		batch := &pgx.Batch{}
+		batch.Queue("select 10/(2-n) from generate_series(0, 5) n")
+		batch.Queue("select 2")
+
+		br := conn.SendBatch(ctx, batch)
+
+		rows, err := br.Query()
+		require.NoError(t, err)
+		rows.Close()
+		var pgErr *pgconn.PgError
+		require.ErrorAs(t, rows.Err(), &pgErr)
+		var rowsBatchErr *pgx.BatchError
+		require.ErrorAs(t, rows.Err(), &rowsBatchErr)
+		require.Equal(t, 0, rowsBatchErr.Index)
+
+		_, err = br.Exec()
+		require.ErrorIs(t, err, pgx.ErrBatchQuerySkipped)
+		var batchErr *pgx.BatchError
+		require.ErrorAs(t, err, &batchErr)
+		require.Equal(t, rowsBatchErr, batchErr)
+
+		err = br.Close()
+		require.Equal(t, rowsBatchErr, err)
+		require.ErrorAs(t, err, &pgErr)
+		require.Equal(t, "22012", pgErr.Code)
+
+		ensureConnValid(t, conn)
+	})
+}
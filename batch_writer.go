@@ -0,0 +1,100 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+)
+
+// batchSender is implemented by *Conn, Tx, and pgxpool.Pool.
+type batchSender interface {
+	SendBatch(ctx context.Context, b *Batch) BatchResults
+}
+
+// BatchWriter queues queries and automatically sends them as a batch whenever the number of queued queries or their
+// approximate size reaches a limit. This allows executing an unbounded number of queries (e.g. in a bulk ingestion loop)
+// without buffering all of them in memory.
+//
+// The size of a query is approximated as the length of its SQL plus the length of any string or []byte arguments. Other
+// arguments are not counted.
+//
+// Each batch is run independently. If a query fails, the queries already sent in earlier batches are not rolled back.
+// Use a Tx as the sender to make all queries atomic.
+type BatchWriter struct {
+	sender     batchSender
+	maxQueries int
+	maxBytes   int
+
+	batch        *Batch
+	size         int
+	sentCount    int
+	rowsAffected int64
+}
+
+// NewBatchWriter returns a BatchWriter that sends batches with sender. sender is typically a *Conn, Tx, or
+// *pgxpool.Pool. A batch is sent when it holds maxQueries queries or maxBytes bytes. A limit of 0 is disabled.
+func NewBatchWriter(sender batchSender, maxQueries, maxBytes int) *BatchWriter {
+	return &BatchWriter{
+		sender:     sender,
+		maxQueries: maxQueries,
+		maxBytes:   maxBytes,
+		batch:      &Batch{},
+	}
+}
+
+// Queue queues a query. If a limit is reached the queued queries are sent, and their results read, before Queue
+// returns. An error returned by sending the batch is returned.
+func (bw *BatchWriter) Queue(ctx context.Context, query string, arguments ...any) error {
+	bw.batch.Queue(query, arguments...)
+
+	bw.size += len(query)
+	for _, arg := range arguments {
+		switch arg := arg.(type) {
+		case string:
+			bw.size += len(arg)
+		case []byte:
+			bw.size += len(arg)
+		}
+	}
+
+	if (bw.maxQueries > 0 && bw.batch.Len() >= bw.maxQueries) || (bw.maxBytes > 0 && bw.size >= bw.maxBytes) {
+		return bw.Flush(ctx)
+	}
+
+	return nil
+}
+
+// Flush sends any queued queries and reads their results. If a query fails, a *BatchError is returned. Its Index is the
+// position of the failed query among all queries queued with bw. The rest of the batch is discarded. Errors that are
+// not caused by a particular query, such as a failure to send the batch, are returned as is.
+func (bw *BatchWriter) Flush(ctx context.Context) error {
+	if bw.batch.Len() == 0 {
+		return nil
+	}
+
+	batch := bw.batch
+	offset := bw.sentCount
+	bw.batch = &Batch{}
+	bw.size = 0
+	bw.sentCount += batch.Len()
+
+	br := bw.sender.SendBatch(ctx, batch)
+	for range batch.QueuedQueries {
+		ct, err := br.Exec()
+		if err != nil {
+			br.Close()
+			var batchErr *BatchError
+			if errors.As(err, &batchErr) {
+				return &BatchError{Index: offset + batchErr.Index, SQL: batchErr.SQL, Err: batchErr.Err}
+			}
+			return err
+		}
+		bw.rowsAffected += ct.RowsAffected()
+	}
+
+	return br.Close()
+}
+
+// RowsAffected returns the total number of rows affected by all queries sent so far.
+func (bw *BatchWriter) RowsAffected() int64 {
+	return bw.rowsAffected
+}
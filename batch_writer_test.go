@@ -0,0 +1,56 @@
+package pgx_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchWriter(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pgxtest.RunWithQueryExecModes(ctx, t, defaultConnTestRunner, nil, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		mustExec(t, conn, "create temporary table t (n int primary key, s text)")
+
+		bw := pgx.NewBatchWriter(conn, 3, 0)
+		for i := 0; i < 10; i++ {
+			err := bw.Queue(ctx, "insert into t (n, s) values ($1, $2)", i, "foo")
+			require.NoError(t, err)
+		}
+
+		var n int64
+		err := conn.QueryRow(ctx, "select count(*) from t").Scan(&n)
+		require.NoError(t, err)
+		require.EqualValues(t, 9, n)
+
+		err = bw.Flush(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 10, bw.RowsAffected())
+
+		err = conn.QueryRow(ctx, "select count(*) from t").Scan(&n)
+		require.NoError(t, err)
+		require.EqualValues(t, 10, n)
+
+		bw = pgx.NewBatchWriter(conn, 0, 100)
+		err = bw.Queue(ctx, "insert into t (n, s) values ($1, $2)", 100, "bar")
+		require.NoError(t, err)
+		err = bw.Queue(ctx, "insert into t (n, s) values ($1, $2)", 1, strings.Repeat("x", 100))
+		var batchErr *pgx.BatchError
+		require.ErrorAs(t, err, &batchErr)
+		require.Equal(t, 1, batchErr.Index)
+		var pgErr *pgconn.PgError
+		require.ErrorAs(t, err, &pgErr)
+		require.Equal(t, "23505", pgErr.Code)
+
+		ensureConnValid(t, conn)
+	})
+}
@@ -41,6 +41,34 @@ type ConnConfig struct {
 	// functionality can be controlled on a per query basis by passing a QueryExecMode as the first query argument.
 	DefaultQueryExecMode QueryExecMode
 
+	// DefaultQueryTimeout is the timeout applied to Exec, Query, QueryRow, SendBatch, Prepare, CopyFrom,
+	// CopyFromReader, and CopyTo when the context passed to them does not have a deadline. It also applies to the statements sent
+	// by Begin, Commit, and Rollback as they are executed with Exec. For Query and SendBatch the timeout covers reading
+	// the results until the Rows or BatchResults are closed. A context with a deadline overrides it for that call. If
+	// it is 0 then no timeout is applied.
+	DefaultQueryTimeout time.Duration
+
+	// ReadOnly sets default_transaction_read_only=on for the session and causes Exec, Query, QueryRow, SendBatch,
+	// Prepare, CopyFrom, and CopyTo to reject obviously mutating statements (e.g. INSERT or CREATE) with ErrReadOnly
+	// before they are sent to the server. Statements that would lift the restriction such as BEGIN READ WRITE or SET
+	// default_transaction_read_only are rejected as well, and BeginTx rejects the ReadWrite access mode and a custom
+	// BeginQuery. The client-side check is not a parser. The server remains responsible for enforcing read-only access.
+	// Note that the lower level *pgconn.PgConn is not restricted.
+	ReadOnly bool
+
+	// RollbackTimeout enables recovery of a connection when the context passed to Tx.Commit or Tx.Rollback of a real
+	// transaction is already done when the method is called. Instead of failing and closing the connection, the
+	// transaction is rolled back with a new context that has this timeout and the connection remains usable. If it is 0
+	// the connection is closed.
+	//
+	// It only covers a context that is done before Commit or Rollback starts. A context that is canceled while a
+	// statement of the transaction or the COMMIT or ROLLBACK itself is running still closes the connection.
+	RollbackTimeout time.Duration
+
+	// TxHooks are called for every real transaction started on the connection. Hooks for a single transaction can be
+	// added with AddTxHooks.
+	TxHooks TxHooks
+
 	createdByParseConfig bool // Used to enforce created by ParseConfig rule.
 }
 
@@ -76,7 +104,10 @@ type Conn struct {
 	copyFromTracer CopyFromTracer
 	prepareTracer  PrepareTracer
 
-	notifications []*pgconn.Notification
+	notifications          []*pgconn.Notification
+	bufferingNotifications bool // true if pgx installed bufferNotifications as the OnNotification handler
+
+	tx *dbTx // most recently started real transaction
 
 	doneChan   chan struct{}
 	closedChan chan error
@@ -270,6 +301,14 @@ func connect(ctx context.Context, config *ConnConfig) (c *Conn, err error) {
 	// Only install pgx notification system if no other callback handler is present.
 	if config.Config.OnNotification == nil {
 		config.Config.OnNotification = c.bufferNotifications
+		c.bufferingNotifications = true
+	}
+
+	if config.ReadOnly {
+		if config.RuntimeParams == nil {
+			config.RuntimeParams = make(map[string]string)
+		}
+		config.RuntimeParams["default_transaction_read_only"] = "on"
 	}
 
 	c.pgConn, err = pgconn.ConnectConfig(ctx, &config.Config)
@@ -314,6 +353,13 @@ func (c *Conn) Close(ctx context.Context) error {
 // Prepare is idempotent; i.e. it is safe to call Prepare multiple times with the same name and sql arguments. This
 // allows a code path to Prepare and Query/Exec without concern for if the statement has already been prepared.
 func (c *Conn) Prepare(ctx context.Context, name, sql string) (sd *pgconn.StatementDescription, err error) {
+	if err := c.checkReadOnly(sql); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.withDefaultQueryTimeout(ctx)
+	defer cancel()
+
 	if c.prepareTracer != nil {
 		ctx = c.prepareTracer.TracePrepareStart(ctx, c, TracePrepareStartData{Name: name, SQL: sql})
 	}
@@ -356,10 +402,15 @@ func (c *Conn) Prepare(ctx context.Context, name, sql string) (sd *pgconn.Statem
 	return sd, nil
 }
 
-// Deallocate releases a prepared statement. Calling Deallocate on a non-existent prepared statement will succeed.
+// Deallocate releases a prepared statement. name may also be the SQL of a statement in the statement cache. Any
+// statement and description cache entries for the statement's SQL are invalidated. Calling Deallocate on a
+// non-existent prepared statement will succeed.
 func (c *Conn) Deallocate(ctx context.Context, name string) error {
 	var psName string
 	sd := c.preparedStatements[name]
+	if sd == nil && c.statementCache != nil {
+		sd = c.statementCache.Get(name)
+	}
 	if sd != nil {
 		psName = sd.Name
 	} else {
@@ -373,6 +424,13 @@ func (c *Conn) Deallocate(ctx context.Context, name string) error {
 
 	if sd != nil {
 		delete(c.preparedStatements, name)
+
+		if c.statementCache != nil {
+			c.statementCache.Invalidate(sd.SQL)
+		}
+		if c.descriptionCache != nil {
+			c.descriptionCache.Invalidate(sd.SQL)
+		}
 	}
 
 	return nil
@@ -391,6 +449,65 @@ func (c *Conn) DeallocateAll(ctx context.Context) error {
 	return err
 }
 
+// Reset returns the session to the state it was in when the connection was established. It executes DISCARD ALL
+// which deallocates all prepared statements, resets run-time parameters to their session defaults, unlistens from all
+// channels, and releases temporary resources. It also clears the statement and description caches and any buffered
+// notifications. Registered types are retained. Reset cannot be called inside a transaction.
+func (c *Conn) Reset(ctx context.Context) error {
+	// DISCARD ALL fails inside a transaction. Check first so the client state is not cleared while the server state is
+	// kept.
+	if txStatus := c.pgConn.TxStatus(); txStatus != 'I' {
+		return fmt.Errorf("cannot reset connection with transaction status %q", txStatus)
+	}
+
+	_, err := c.pgConn.Exec(ctx, "discard all").ReadAll()
+	if err != nil {
+		return err
+	}
+
+	c.preparedStatements = map[string]*pgconn.StatementDescription{}
+	if c.config.StatementCacheCapacity > 0 {
+		c.statementCache = stmtcache.NewLRUCache(c.config.StatementCacheCapacity)
+	}
+	if c.config.DescriptionCacheCapacity > 0 {
+		c.descriptionCache = stmtcache.NewLRUCache(c.config.DescriptionCacheCapacity)
+	}
+	c.notifications = nil
+	return nil
+}
+
+// ValidateQuery checks that the results of sql can be scanned into dest without executing sql. The statement is
+// described by the server with the unnamed prepared statement. An error is returned if the number of result columns
+// does not match the number of destinations or any column type cannot be scanned into the corresponding destination.
+// nil destinations are not checked. As with Scan, scanning particular values may still fail. e.g. NULL into a *int32.
+func (c *Conn) ValidateQuery(ctx context.Context, sql string, dest ...any) error {
+	sd, err := c.Prepare(ctx, "", sql)
+	if err != nil {
+		return err
+	}
+
+	if len(sd.Fields) != len(dest) {
+		return fmt.Errorf("number of field descriptions must equal number of destinations, got %d and %d", len(sd.Fields), len(dest))
+	}
+
+	for i, d := range dest {
+		if d == nil {
+			continue
+		}
+
+		oid := sd.Fields[i].DataTypeOID
+		if !c.typeMap.CanScan(oid, c.typeMap.FormatCodeForOID(oid), d) {
+			dataTypeName := "unknown type"
+			if t, ok := c.typeMap.TypeForOID(oid); ok {
+				dataTypeName = t.Name
+			}
+			return newScanArgError(&sd.Fields[i], i, d, fmt.Errorf("cannot scan %s (OID %d) into %T", dataTypeName, oid, d))
+		}
+	}
+
+	return nil
+}
+
 func (c *Conn) bufferNotifications(_ *pgconn.PgConn, n *pgconn.Notification) {
 	c.notifications = append(c.notifications, n)
 }
@@ -446,15 +563,53 @@ func (c *Conn) Ping(ctx context.Context) error {
 // is used and the connection must be returned to the same state before any *pgx.Conn methods are again used.
 func (c *Conn) PgConn() *pgconn.PgConn { return c.pgConn }
 
+// Hijack detaches the underlying *pgconn.PgConn from c and returns it. This is useful when pgx is used to establish the
+// connection and load types, but lower level protocol features (e.g. replication) are needed afterwards. c is closed
+// and must not be used again. The network connection remains open and the caller is responsible for closing the
+// returned *pgconn.PgConn. Prepared statements created through c remain on the server. Notifications buffered by c
+// are discarded. If the notification handler was installed by pgx, the returned *pgconn.PgConn has no
+// OnNotification handler.
+//
+// c must be idle (no in-progress queries).
+func (c *Conn) Hijack() (*pgconn.PgConn, error) {
+	hc, err := c.pgConn.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	// Otherwise notifications received by the returned *pgconn.PgConn would be buffered in c forever.
+	if c.bufferingNotifications {
+		hc.Config = hc.Config.Copy()
+		hc.Config.OnNotification = nil
+	}
+	c.notifications = nil
+
+	return pgconn.Construct(hc)
+}
+
 // TypeMap returns the connection info used for this connection.
 func (c *Conn) TypeMap() *pgtype.Map { return c.typeMap }
 
 // Config returns a copy of config that was used to establish this connection.
 func (c *Conn) Config() *ConnConfig { return c.config.Copy() }
 
+// withDefaultQueryTimeout returns ctx with ConnConfig.DefaultQueryTimeout applied if ctx does not have a deadline. The
+// returned cancel function must be called when the operation is done.
+func (c *Conn) withDefaultQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.config.DefaultQueryTimeout > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			return context.WithTimeout(ctx, c.config.DefaultQueryTimeout)
+		}
+	}
+	return ctx, func() {}
+}
+
 // Exec executes sql. sql can be either a prepared statement name or an SQL string. arguments should be referenced
 // positionally from the sql string as $1, $2, etc.
 func (c *Conn) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	ctx, cancel := c.withDefaultQueryTimeout(ctx)
+	defer cancel()
+
 	if c.queryTracer != nil {
 		ctx = c.queryTracer.TraceQueryStart(ctx, c, TraceQueryStartData{SQL: sql, Args: arguments})
 	}
@@ -497,6 +652,10 @@ optionLoop:
 		}
 	}
 
+	if err := c.checkReadOnly(sql); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
 	// Always use simple protocol when there are no arguments.
 	if len(arguments) == 0 {
 		mode = QueryExecModeSimpleProtocol
@@ -520,7 +679,26 @@ optionLoop:
 			c.statementCache.Put(sd)
 		}
 
-		return c.execPrepared(ctx, sd, arguments)
+		commandTag, err = c.execPrepared(ctx, sd, arguments)
+		if err != nil && isCachedStatementInvalid(err) {
+			c.statementCache.Invalidate(sql)
+
+			// The statement could not have been executed so it is safe to prepare it again and retry once. But if a
+			// transaction is in progress it has been aborted and retrying would only fail again.
+			if c.pgConn.TxStatus() == 'I' {
+				if err := c.deallocateInvalidatedCachedStatements(ctx); err != nil {
+					return pgconn.CommandTag{}, err
+				}
+				sd, err = c.Prepare(ctx, stmtcache.StatementName(sql), sql)
+				if err != nil {
+					return pgconn.CommandTag{}, err
+				}
+				c.statementCache.Put(sd)
+				return c.execPrepared(ctx, sd, arguments)
+			}
+		}
+
+		return commandTag, err
 	case QueryExecModeCacheDescribe:
 		if c.descriptionCache == nil {
 			return pgconn.CommandTag{}, errDisabledDescriptionCache
@@ -550,6 +728,24 @@ optionLoop:
 	}
 }
 
+// isCachedStatementInvalid returns true if err indicates that a cached prepared statement is stale (e.g. because the
+// schema changed) or no longer exists on the server.
+func isCachedStatementInvalid(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	switch pgErr.Code {
+	case "26000": // invalid_sql_statement_name
+		return true
+	case "0A000": // feature_not_supported
+		return strings.Contains(pgErr.Message, "cached plan must not change result type")
+	default:
+		return false
+	}
+}
+
 func (c *Conn) execSimpleProtocol(ctx context.Context, sql string, arguments []any) (commandTag pgconn.CommandTag, err error) {
 	if len(arguments) > 0 {
 		sql, err = c.sanitizeForSimpleQuery(sql, arguments...)
@@ -621,7 +817,8 @@ const (
 	// Automatically prepare and cache statements. This uses the extended protocol. Queries are executed in a single round
 	// trip after the statement is cached. This is the default. If the database schema is modified or the search_path is
 	// changed after a statement is cached then the first execution of a previously cached query may fail. e.g. If the
-	// number of columns returned by a "SELECT *" changes or the type of a column is changed.
+	// number of columns returned by a "SELECT *" changes or the type of a column is changed. Exec automatically prepares
+	// the statement again and retries once when this happens outside of a transaction.
 	QueryExecModeCacheStatement
 
 	// Cache statement descriptions (i.e. argument and result types) and assume they do not change. This uses the extended
@@ -721,6 +918,19 @@ type QueryRewriter interface {
 // QueryResultFormatsByOID may be used as the first args to control exactly how the query is executed. This is rarely
 // needed. See the documentation for those types for details.
 func (c *Conn) Query(ctx context.Context, sql string, args ...any) (Rows, error) {
+	if c.config.DefaultQueryTimeout > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			ctx, cancel := context.WithTimeout(ctx, c.config.DefaultQueryTimeout)
+			rows, err := c.Query(ctx, sql, args...)
+			if br, ok := rows.(*baseRows); ok && !br.closed {
+				br.cancelCtx = cancel
+			} else {
+				cancel()
+			}
+			return rows, err
+		}
+	}
+
 	if c.queryTracer != nil {
 		ctx = c.queryTracer.TraceQueryStart(ctx, c, TraceQueryStartData{SQL: sql, Args: args})
 	}
@@ -770,6 +980,12 @@ optionLoop:
 		}
 	}
 
+	if err := c.checkReadOnly(sql); err != nil {
+		rows := c.getRows(ctx, sql, args)
+		rows.fatal(err)
+		return rows, err
+	}
+
 	// Bypass any statement caching.
 	if sql == "" {
 		mode = QueryExecModeSimpleProtocol
@@ -911,6 +1127,22 @@ func (c *Conn) QueryRow(ctx context.Context, sql string, args ...any) Row {
 // Depending on the QueryExecMode, all queries may be prepared before any are executed. This means that creating a table
 // and using it in a subsequent query in the same batch can fail.
 func (c *Conn) SendBatch(ctx context.Context, b *Batch) (br BatchResults) {
+	if c.config.DefaultQueryTimeout > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			ctx, cancel := context.WithTimeout(ctx, c.config.DefaultQueryTimeout)
+			br := c.SendBatch(ctx, b)
+			switch br := br.(type) {
+			case *batchResults:
+				br.cancelCtx = cancel
+			case *pipelineBatchResults:
+				br.cancelCtx = cancel
+			default:
+				cancel()
+			}
+			return br
+		}
+	}
+
 	if c.batchTracer != nil {
 		ctx = c.batchTracer.TraceBatchStart(ctx, c, TraceBatchStartData{Batch: b})
 		defer func() {
@@ -950,6 +1182,10 @@ func (c *Conn) SendBatch(ctx context.Context, b *Batch) (br BatchResults) {
 			}
 		}
 
+		if err := c.checkReadOnly(sql); err != nil {
+			return &batchResults{ctx: ctx, conn: c, err: err}
+		}
+
 		bi.SQL = sql
 		bi.Arguments = arguments
 	}
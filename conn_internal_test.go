@@ -53,3 +53,25 @@ func TestStmtCacheSizeLimit(t *testing.T) {
 	assert.Len(t, conn.preparedStatements, cacheLimit+1)
 	assert.Equal(t, cacheLimit, conn.statementCache.Len())
 }
+
+// Ensures notifications received after Hijack are not buffered in the abandoned *Conn.
+// This test examines the internals of *Conn so must be in the same package.
+func TestHijackDoesNotBufferNotifications(t *testing.T) {
+	ctx := context.Background()
+
+	conn := mustConnect(t, mustParseConfig(t, os.Getenv("PGX_TEST_DATABASE")))
+	if conn.PgConn().ParameterStatus("crdb_version") != "" {
+		t.Skip("Server does not support LISTEN / NOTIFY (https://github.com/cockroachdb/cockroach/issues/41522)")
+	}
+
+	pgConn, err := conn.Hijack()
+	require.NoError(t, err)
+	defer pgConn.Close(ctx)
+
+	_, err = pgConn.Exec(ctx, "listen hijacked").ReadAll()
+	require.NoError(t, err)
+	_, err = pgConn.Exec(ctx, "notify hijacked, 'payload'").ReadAll()
+	require.NoError(t, err)
+
+	require.Empty(t, conn.notifications)
+}
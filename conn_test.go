@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"io"
 	"os"
 	"strings"
 	"sync"
@@ -373,6 +374,264 @@ func TestExecContextFailureWithoutCancelationWithArguments(t *testing.T) {
 	})
 }
 
+func TestDefaultQueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	config := mustParseConfig(t, os.Getenv("PGX_TEST_DATABASE"))
+	config.DefaultQueryTimeout = 50 * time.Millisecond
+	conn := mustConnect(t, config)
+	defer closeConn(t, conn)
+
+	_, err := conn.Exec(context.Background(), "select pg_sleep(5)")
+	require.Error(t, err)
+	require.True(t, pgconn.Timeout(err))
+	ensureConnValid(t, conn)
+
+	var n int32
+	err = conn.QueryRow(context.Background(), "select 1 from pg_sleep(5)").Scan(&n)
+	require.Error(t, err)
+	require.True(t, pgconn.Timeout(err))
+	ensureConnValid(t, conn)
+
+	batch := &pgx.Batch{}
+	batch.Queue("select pg_sleep(5)")
+	err = conn.SendBatch(context.Background(), batch).Close()
+	require.Error(t, err)
+	require.True(t, pgconn.Timeout(err))
+	ensureConnValid(t, conn)
+
+	_, err = conn.CopyTo(context.Background(), io.Discard, "select pg_sleep(5)", pgx.CopyFormatText)
+	require.Error(t, err)
+	require.True(t, pgconn.Timeout(err))
+	ensureConnValid(t, conn)
+
+	// A context with a deadline overrides DefaultQueryTimeout.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err = conn.QueryRow(ctx, "select 1 from pg_sleep(0.2)").Scan(&n)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+	ensureConnValid(t, conn)
+}
+
+func TestConnHijack(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	pid := conn.PgConn().PID()
+
+	pgConn, err := conn.Hijack()
+	require.NoError(t, err)
+	defer pgConn.Close(ctx)
+
+	require.True(t, conn.IsClosed())
+	_, err = conn.Exec(ctx, "select 1")
+	require.Error(t, err)
+
+	require.Equal(t, pid, pgConn.PID())
+	results, err := pgConn.Exec(ctx, "select 1").ReadAll()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "1", string(results[0].Rows[0][0]))
+}
+
+func TestExecRetriesInvalidCachedStatement(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config := mustParseConfig(t, os.Getenv("PGX_TEST_DATABASE"))
+	config.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+	conn := mustConnect(t, config)
+	defer closeConn(t, conn)
+
+	pgxtest.SkipCockroachDB(t, conn, "Server does not support cached plan result type errors")
+
+	mustExec(t, conn, "create temporary table t (a int)")
+
+	sql := "select * from t where $1::int is not null"
+	_, err := conn.Exec(ctx, sql, 1)
+	require.NoError(t, err)
+
+	mustExec(t, conn, "alter table t add column b text")
+	_, err = conn.Exec(ctx, sql, 1)
+	require.NoError(t, err)
+
+	_, err = conn.PgConn().Exec(ctx, "deallocate all").ReadAll()
+	require.NoError(t, err)
+	_, err = conn.Exec(ctx, sql, 1)
+	require.NoError(t, err)
+
+	// Retrying is not possible inside a transaction, but the stale statement is removed from the cache.
+	tx, err := conn.Begin(ctx)
+	require.NoError(t, err)
+	_, err = tx.Exec(ctx, "alter table t add column c text")
+	require.NoError(t, err)
+	_, err = tx.Exec(ctx, sql, 1)
+	require.Error(t, err)
+	require.NoError(t, tx.Rollback(ctx))
+
+	_, err = conn.Exec(ctx, sql, 1)
+	require.NoError(t, err)
+
+	ensureConnValid(t, conn)
+}
+
+func TestConnReadOnly(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config := mustParseConfig(t, os.Getenv("PGX_TEST_DATABASE"))
+	config.ReadOnly = true
+	conn := mustConnect(t, config)
+	defer closeConn(t, conn)
+
+	var readOnly string
+	err := conn.QueryRow(ctx, "show default_transaction_read_only").Scan(&readOnly)
+	require.NoError(t, err)
+	require.Equal(t, "on", readOnly)
+
+	for _, sql := range []string{
+		"insert into t values (1)",
+		"  UPDATE t set a = 1",
+		"-- comment\n/* nested /* comment */ */ (delete from t)",
+		"create table t (a int)",
+	} {
+		_, err = conn.Exec(ctx, sql)
+		require.ErrorIsf(t, err, pgx.ErrReadOnly, "%s", sql)
+
+		rows, err := conn.Query(ctx, sql)
+		require.ErrorIsf(t, err, pgx.ErrReadOnly, "%s", sql)
+		rows.Close()
+
+		_, err = conn.Prepare(ctx, "ps", sql)
+		require.ErrorIsf(t, err, pgx.ErrReadOnly, "%s", sql)
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue("select 1")
+	batch.Queue("delete from t")
+	err = conn.SendBatch(ctx, batch).Close()
+	require.ErrorIs(t, err, pgx.ErrReadOnly)
+
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"t"}, []string{"a"}, pgx.CopyFromRows([][]any{{1}}))
+	require.ErrorIs(t, err, pgx.ErrReadOnly)
+
+	var n int32
+	err = conn.QueryRow(ctx, "select $1::int4", 42).Scan(&n)
+	require.NoError(t, err)
+	require.EqualValues(t, 42, n)
+
+	// Statements that would lift the read-only restriction are rejected.
+	for _, sql := range []string{
+		"begin read write",
+		"START TRANSACTION ISOLATION LEVEL SERIALIZABLE, READ  WRITE",
+		"set default_transaction_read_only = off",
+		"SET SESSION default_transaction_read_only TO false",
+		"set local transaction_read_only = off",
+		"reset default_transaction_read_only",
+		"set session characteristics as transaction read write",
+		"set transaction read write",
+		"select set_config('default_transaction_read_only', 'off', false)",
+	} {
+		_, err = conn.Exec(ctx, sql)
+		require.ErrorIsf(t, err, pgx.ErrReadOnly, "%s", sql)
+	}
+
+	_, err = conn.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadWrite})
+	require.ErrorIs(t, err, pgx.ErrReadOnly)
+
+	_, err = conn.BeginTx(ctx, pgx.TxOptions{BeginQuery: "begin"})
+	require.ErrorIs(t, err, pgx.ErrReadOnly)
+
+	err = conn.QueryRow(ctx, "show default_transaction_read_only").Scan(&readOnly)
+	require.NoError(t, err)
+	require.Equal(t, "on", readOnly)
+
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	require.NoError(t, err)
+	for _, name := range []string{"transaction_read_only", "Default_Transaction_Read_Only"} {
+		err = pgx.SetLocal(ctx, tx, name, "off")
+		require.ErrorIsf(t, err, pgx.ErrReadOnly, "%s", name)
+	}
+	require.NoError(t, pgx.SetLocal(ctx, tx, "application_name", "pgx read-only test"))
+	require.NoError(t, tx.Rollback(ctx))
+
+	ensureConnValid(t, conn)
+}
+
+func TestExecMismatchedArgumentCount(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	modes := []pgx.QueryExecMode{
+		pgx.QueryExecModeCacheStatement,
+		pgx.QueryExecModeCacheDescribe,
+		pgx.QueryExecModeDescribeExec,
+	}
+
+	pgxtest.RunWithQueryExecModes(ctx, t, defaultConnTestRunner, modes, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		_, err := conn.Exec(ctx, "select $1::int4, $2::int4", 1)
+		require.EqualError(t, err, `mismatched param and argument count: expected 2 arguments, got 1 for "select $1::int4, $2::int4"`)
+		ensureConnValid(t, conn)
+	})
+}
+
+func TestConnReset(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	pgxtest.SkipCockroachDB(t, conn, "Server does not support DISCARD ALL")
+
+	_, err := conn.Prepare(ctx, "ps", "select 1")
+	require.NoError(t, err)
+	mustExec(t, conn, "select $1::int4", 1)
+	mustExec(t, conn, "set application_name = 'reset test'")
+	mustExec(t, conn, "listen reset_test")
+
+	err = conn.Reset(ctx)
+	require.NoError(t, err)
+
+	var n int64
+	err = conn.QueryRow(ctx, "select count(*) from pg_prepared_statements").Scan(&n)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, n)
+
+	var appName string
+	err = conn.QueryRow(ctx, "show application_name").Scan(&appName)
+	require.NoError(t, err)
+	require.NotEqual(t, "reset test", appName)
+
+	err = conn.QueryRow(ctx, "select count(*) from pg_listening_channels()").Scan(&n)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, n)
+
+	// Reset fails inside a transaction without forgetting the prepared statements known to the server.
+	_, err = conn.Prepare(ctx, "ps", "select 1")
+	require.NoError(t, err)
+	tx, err := conn.Begin(ctx)
+	require.NoError(t, err)
+	require.Error(t, conn.Reset(ctx))
+	require.NoError(t, tx.Rollback(ctx))
+	_, err = conn.Prepare(ctx, "ps", "select 1")
+	require.NoError(t, err)
+
+	ensureConnValid(t, conn)
+}
+
 func TestExecFailureCloseBefore(t *testing.T) {
 	t.Parallel()
 
@@ -467,6 +726,33 @@ func TestPrepare(t *testing.T) {
 	}
 }
 
+func TestValidateQuery(t *testing.T) {
+	t.Parallel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	var n int32
+	var s string
+	var tm time.Time
+
+	err := conn.ValidateQuery(context.Background(), "select 1::int4, 'foo'::text, now()", &n, &s, &tm)
+	require.NoError(t, err)
+
+	err = conn.ValidateQuery(context.Background(), "select 1::int4, 'foo'::text, now()", &n, nil, nil)
+	require.NoError(t, err)
+
+	err = conn.ValidateQuery(context.Background(), "select 1::int4, now()", &n, &n)
+	var scanArgErr pgx.ScanArgError
+	require.ErrorAs(t, err, &scanArgErr)
+	require.Equal(t, 1, scanArgErr.ColumnIndex)
+
+	err = conn.ValidateQuery(context.Background(), "select 1::int4, 'foo'::text", &n)
+	require.EqualError(t, err, "number of field descriptions must equal number of destinations, got 2 and 1")
+
+	ensureConnValid(t, conn)
+}
+
 func TestPrepareBadSQLFailure(t *testing.T) {
 	t.Parallel()
 
@@ -614,6 +900,29 @@ func TestDeallocateMissingPreparedStatementStillClearsFromPreparedStatementMap(t
 	})
 }
 
+func TestDeallocateInvalidatesStatementCache(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pgxtest.RunWithQueryExecModes(ctx, t, defaultConnTestRunner, []pgx.QueryExecMode{pgx.QueryExecModeCacheStatement}, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		sql := "select $1::text"
+
+		var s string
+		err := conn.QueryRow(ctx, sql, "hello").Scan(&s)
+		require.NoError(t, err)
+		require.Equal(t, "hello", s)
+
+		err = conn.Deallocate(ctx, sql)
+		require.NoError(t, err)
+
+		err = conn.QueryRow(ctx, sql, "world").Scan(&s)
+		require.NoError(t, err)
+		require.Equal(t, "world", s)
+	})
+}
+
 func TestListenNotify(t *testing.T) {
 	t.Parallel()
 
@@ -3,13 +3,26 @@ package pgx
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/internal/pgio"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// ErrCopyCanceled is returned (wrapped with the context error) by CopyFrom when the context is canceled while the copy
+// is in progress and the copy was aborted without closing the connection.
+var ErrCopyCanceled = errors.New("copy canceled")
+
+// copyFromCancelGracePeriod is how long CopyFrom waits for the server to acknowledge an aborted copy after the context
+// is canceled. If the server does not respond in time the context cancellation is applied to the connection itself.
+const copyFromCancelGracePeriod = 5 * time.Second
+
 // CopyFromRows returns a CopyFromSource interface over the provided rows slice
 // making it usable by *Conn.CopyFrom.
 func CopyFromRows(rows [][]any) CopyFromSource {
@@ -91,6 +104,338 @@ func (g *copyFromFunc) Err() error {
 	return g.err
 }
 
+// CopyFromChan returns a CopyFromSource interface that receives rows from ch until it is closed, making it usable by
+// *Conn.CopyFrom. As each row is only received when the copy is ready for it, a producer goroutine sending to ch is
+// limited to the speed of the copy. If the copy fails, rows are no longer received from ch. The producer should
+// therefore also stop when CopyFrom returns, e.g. by selecting on a context that is canceled at that point. Use
+// CopyFromFunc if the producer needs to abort the copy with an error.
+func CopyFromChan(ch <-chan []any) CopyFromSource {
+	return &copyFromChan{ch: ch}
+}
+
+type copyFromChan struct {
+	ch       <-chan []any
+	valueRow []any
+}
+
+func (cfc *copyFromChan) Next() bool {
+	var ok bool
+	cfc.valueRow, ok = <-cfc.ch
+	return ok
+}
+
+func (cfc *copyFromChan) Values() ([]any, error) {
+	return cfc.valueRow, nil
+}
+
+func (cfc *copyFromChan) Err() error {
+	return nil
+}
+
+// CopyFromStructs returns a CopyFromSource interface over the provided rows slice making it usable by *Conn.CopyFrom.
+// The values of each row are the exported fields of T. A db struct tag sets the column name, a db tag of "-" skips the
+// field, and the fields of embedded structs are included with the db tag of the embedded struct as a prefix, as with
+// RowToStructByName. Untagged fields are not mapped the same way. RowToStructByName matches them to columns ignoring
+// case and underscores, but CopyFromStructs uses the field name folded to lower case as PostgreSQL does with unquoted
+// identifiers. e.g. An untagged field CreatedAt is copied to the column createdat, not created_at. Tag the field with
+// db:"created_at" to copy it to created_at. If columnNames is nil *Conn.CopyFrom uses the columns derived from T.
+func CopyFromStructs[T any](rows []T) CopyFromSource {
+	cfs := &copyFromStructs[T]{rows: rows, idx: -1}
+
+	t := reflect.TypeOf(rows).Elem()
+	if t.Kind() != reflect.Struct {
+		cfs.err = fmt.Errorf("CopyFromStructs requires a struct type, got %v", t)
+		return cfs
+	}
+	cfs.columns, cfs.paths = computeCopyFromStructColumns(t)
+
+	return cfs
+}
+
+type copyFromStructs[T any] struct {
+	rows    []T
+	idx     int
+	columns []string
+	paths   [][]int
+	err     error
+}
+
+func (cfs *copyFromStructs[T]) Next() bool {
+	if cfs.err != nil {
+		return false
+	}
+	cfs.idx++
+	return cfs.idx < len(cfs.rows)
+}
+
+func (cfs *copyFromStructs[T]) Values() ([]any, error) {
+	row := reflect.ValueOf(&cfs.rows[cfs.idx]).Elem()
+	values := make([]any, len(cfs.paths))
+	for i, path := range cfs.paths {
+		values[i] = row.FieldByIndex(path).Interface()
+	}
+	return values, nil
+}
+
+func (cfs *copyFromStructs[T]) Err() error {
+	return cfs.err
+}
+
+func (cfs *copyFromStructs[T]) columnNames() []string {
+	return cfs.columns
+}
+
+func computeCopyFromStructColumns(t reflect.Type) ([]string, [][]int) {
+	var columns []string
+	var paths [][]int
+	fieldStack := make([]int, 0, 1)
+	visitStructColumnFields(t, "", &fieldStack, func(prefix, name string, tagged bool, path []int) {
+		if !tagged {
+			name = strings.ToLower(name)
+		}
+		columns = append(columns, prefix+name)
+		paths = append(paths, append([]int(nil), path...))
+	})
+
+	return columns, paths
+}
+
+// CopyFromMaps returns a CopyFromSource interface over the provided rows slice making it usable by *Conn.CopyFrom.
+// The columns are the keys of all maps in sorted order. columnNames may be nil when calling *Conn.CopyFrom. A row that
+// does not have a key is copied as NULL for that column.
+func CopyFromMaps(rows []map[string]any) CopyFromSource {
+	keys := make(map[string]struct{})
+	for _, row := range rows {
+		for k := range row {
+			keys[k] = struct{}{}
+		}
+	}
+
+	columns := make([]string, 0, len(keys))
+	for k := range keys {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+
+	return &copyFromMaps{rows: rows, idx: -1, columns: columns}
+}
+
+type copyFromMaps struct {
+	rows    []map[string]any
+	idx     int
+	columns []string
+}
+
+func (cfm *copyFromMaps) Next() bool {
+	cfm.idx++
+	return cfm.idx < len(cfm.rows)
+}
+
+func (cfm *copyFromMaps) Values() ([]any, error) {
+	row := cfm.rows[cfm.idx]
+	values := make([]any, len(cfm.columns))
+	for i, col := range cfm.columns {
+		values[i] = row[col]
+	}
+	return values, nil
+}
+
+func (cfm *copyFromMaps) Err() error {
+	return nil
+}
+
+func (cfm *copyFromMaps) columnNames() []string {
+	return cfm.columns
+}
+
+// CopyProgress reports the progress of a *Conn.CopyFrom.
+type CopyProgress struct {
+	Rows    int64         // Number of rows read from the source and encoded.
+	Bytes   int64         // Number of bytes of encoded rows sent.
+	Elapsed time.Duration // Time since the first row was requested.
+}
+
+// CopyFromWithProgress returns a CopyFromSource that wraps src and calls fn whenever at least everyRows rows have been
+// encoded or everyBytes bytes have been sent since the last call. A limit of 0 is disabled. When all copy data has been
+// sent fn is called a final time if there is progress that was not reported yet. fn is called from the
+// goroutine that encodes the copy data and it must not block for long as the copy does not progress while fn is
+// running.
+func CopyFromWithProgress(src CopyFromSource, everyRows int64, everyBytes int64, fn func(CopyProgress)) CopyFromSource {
+	return &copyFromProgress{
+		CopyFromSource: src,
+		everyRows:      everyRows,
+		everyBytes:     everyBytes,
+		fn:             fn,
+	}
+}
+
+type copyFromProgress struct {
+	CopyFromSource
+	everyRows  int64
+	everyBytes int64
+	fn         func(CopyProgress)
+
+	startTime     time.Time
+	progress      CopyProgress
+	reportedRows  int64
+	reportedBytes int64
+}
+
+func (cfp *copyFromProgress) Next() bool {
+	if cfp.startTime.IsZero() {
+		cfp.startTime = time.Now()
+	}
+
+	if !cfp.CopyFromSource.Next() {
+		return false
+	}
+
+	cfp.progress.Rows++
+	cfp.report()
+	return true
+}
+
+func (cfp *copyFromProgress) columnNames() []string {
+	if src, ok := cfp.CopyFromSource.(copyFromColumnNamer); ok {
+		return src.columnNames()
+	}
+	return nil
+}
+
+func (cfp *copyFromProgress) addBytes(n int) {
+	if src, ok := cfp.CopyFromSource.(copyFromByteCounter); ok {
+		src.addBytes(n)
+	}
+	cfp.progress.Bytes += int64(n)
+	cfp.report()
+}
+
+func (cfp *copyFromProgress) copyStart(ctx context.Context) {
+	if src, ok := cfp.CopyFromSource.(copyFromStarter); ok {
+		src.copyStart(ctx)
+	}
+}
+
+func (cfp *copyFromProgress) copyDone() {
+	if src, ok := cfp.CopyFromSource.(copyFromDoner); ok {
+		src.copyDone()
+	}
+	if cfp.progress.Rows != cfp.reportedRows || cfp.progress.Bytes != cfp.reportedBytes {
+		cfp.reportNow()
+	}
+}
+
+func (cfp *copyFromProgress) report() {
+	if (cfp.everyRows > 0 && cfp.progress.Rows-cfp.reportedRows >= cfp.everyRows) ||
+		(cfp.everyBytes > 0 && cfp.progress.Bytes-cfp.reportedBytes >= cfp.everyBytes) {
+		cfp.reportNow()
+	}
+}
+
+func (cfp *copyFromProgress) reportNow() {
+	cfp.reportedRows = cfp.progress.Rows
+	cfp.reportedBytes = cfp.progress.Bytes
+	cfp.progress.Elapsed = time.Since(cfp.startTime)
+	cfp.fn(cfp.progress)
+}
+
+// CopyFromWithRateLimit returns a CopyFromSource that wraps src and limits the copy to rowsPerSecond rows and
+// bytesPerSecond bytes of encoded data. A limit of 0 is disabled. This keeps a large bulk load from saturating the
+// server's WAL or replication.
+//
+// Rows are throttled as they are read from src. Bytes are throttled after each chunk of copy data is sent. The chunk
+// size is not configurable. A chunk is at most 64 KiB so the rate of bytes cannot exceed the limit by more than that.
+// The delays apply to the goroutine encoding the copy data. A delay ends early when the context of *Conn.CopyFrom is
+// canceled.
+func CopyFromWithRateLimit(src CopyFromSource, rowsPerSecond float64, bytesPerSecond float64) CopyFromSource {
+	return &copyFromRateLimit{
+		CopyFromSource: src,
+		rowsPerSecond:  rowsPerSecond,
+		bytesPerSecond: bytesPerSecond,
+		ctx:            context.Background(),
+	}
+}
+
+type copyFromRateLimit struct {
+	CopyFromSource
+	rowsPerSecond  float64
+	bytesPerSecond float64
+	ctx            context.Context // context of the copy. Delays end when it is done.
+
+	startTime time.Time
+	rows      int64
+	bytes     int64
+}
+
+func (cfrl *copyFromRateLimit) Next() bool {
+	if cfrl.startTime.IsZero() {
+		cfrl.startTime = time.Now()
+	}
+
+	if cfrl.rowsPerSecond > 0 {
+		cfrl.waitUntil(float64(cfrl.rows) / cfrl.rowsPerSecond)
+	}
+
+	// CopyFrom aborts the copy when it finds the context done after Next returns false.
+	if cfrl.ctx.Err() != nil {
+		return false
+	}
+
+	if !cfrl.CopyFromSource.Next() {
+		return false
+	}
+
+	cfrl.rows++
+	return true
+}
+
+func (cfrl *copyFromRateLimit) columnNames() []string {
+	if src, ok := cfrl.CopyFromSource.(copyFromColumnNamer); ok {
+		return src.columnNames()
+	}
+	return nil
+}
+
+func (cfrl *copyFromRateLimit) addBytes(n int) {
+	if src, ok := cfrl.CopyFromSource.(copyFromByteCounter); ok {
+		src.addBytes(n)
+	}
+
+	cfrl.bytes += int64(n)
+	if cfrl.bytesPerSecond > 0 {
+		cfrl.waitUntil(float64(cfrl.bytes) / cfrl.bytesPerSecond)
+	}
+}
+
+func (cfrl *copyFromRateLimit) copyStart(ctx context.Context) {
+	if src, ok := cfrl.CopyFromSource.(copyFromStarter); ok {
+		src.copyStart(ctx)
+	}
+	cfrl.ctx = ctx
+}
+
+func (cfrl *copyFromRateLimit) copyDone() {
+	if src, ok := cfrl.CopyFromSource.(copyFromDoner); ok {
+		src.copyDone()
+	}
+}
+
+// waitUntil waits until seconds have elapsed since the first row was requested or until the context of the copy is
+// done.
+func (cfrl *copyFromRateLimit) waitUntil(seconds float64) {
+	d := time.Duration(seconds*float64(time.Second)) - time.Since(cfrl.startTime)
+	if d <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-cfrl.ctx.Done():
+	}
+}
+
 // CopyFromSource is the interface used by *Conn.CopyFrom as the source for copy data.
 type CopyFromSource interface {
 	// Next returns true if there is another row and makes the next row data
@@ -106,6 +451,28 @@ type CopyFromSource interface {
 	Err() error
 }
 
+// copyFromColumnNamer is implemented by a CopyFromSource that knows its column names. CopyFrom and CopyUpsert use them
+// when columnNames is nil.
+type copyFromColumnNamer interface {
+	columnNames() []string
+}
+
+// copyFromByteCounter is implemented by a CopyFromSource that is told how many bytes of encoded copy data were sent.
+type copyFromByteCounter interface {
+	addBytes(n int)
+}
+
+// copyFromStarter is implemented by a CopyFromSource that is given the context of the copy before the first row is
+// requested.
+type copyFromStarter interface {
+	copyStart(ctx context.Context)
+}
+
+// copyFromDoner is implemented by a CopyFromSource that is told when all copy data was sent.
+type copyFromDoner interface {
+	copyDone()
+}
+
 type copyFrom struct {
 	conn          *Conn
 	tableName     Identifier
@@ -113,6 +480,37 @@ type copyFrom struct {
 	rowSrc        CopyFromSource
 	readerErrChan chan error
 	mode          QueryExecMode
+	rowIdx        int64             // index of the source row being encoded
+	rowErr        *CopyFromRowError // set when a source row could not be read or encoded
+}
+
+// CopyFromRowError is returned by CopyFrom when a row cannot be read from the CopyFromSource or encoded. The copy is
+// aborted. The *pgconn.PgError the server responds with to the aborted copy is also returned by errors.As.
+type CopyFromRowError struct {
+	// RowIndex is the index of the source row.
+	RowIndex int64
+
+	// Column is the name of the column that could not be encoded. It is empty if the row itself could not be read.
+	Column string
+
+	Err error
+
+	copyErr error // error returned for the aborted copy
+}
+
+func (e CopyFromRowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("source row %d: %v", e.RowIndex, e.Err)
+	}
+
+	return fmt.Sprintf("source row %d, column %s: %v", e.RowIndex, e.Column, e.Err)
+}
+
+func (e CopyFromRowError) Unwrap() []error {
+	if e.copyErr == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.copyErr}
 }
 
 func (ct *copyFrom) run(ctx context.Context) (int64, error) {
@@ -152,6 +550,12 @@ func (ct *copyFrom) run(ctx context.Context) (int64, error) {
 			fmt.Sprintf("select %s from %s", quotedColumnNames, quotedTableName),
 		)
 		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "42703" {
+				if columnsErr := ct.checkColumns(ctx, err); columnsErr != nil {
+					return 0, columnsErr
+				}
+			}
 			return 0, fmt.Errorf("statement description failed: %w", err)
 		}
 	default:
@@ -161,22 +565,59 @@ func (ct *copyFrom) run(ctx context.Context) (int64, error) {
 	r, w := io.Pipe()
 	doneChan := make(chan struct{})
 
+	// The copy is run with a context that is not canceled when ctx is. Instead, when ctx is canceled the copy data stream
+	// is aborted which causes PgConn.CopyFrom to send CopyFail, read the server's response, and leave the connection
+	// usable. The real cancellation is only applied if the server does not respond within copyFromCancelGracePeriod.
+	copyCtx, cancelCopyCtx := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelCopyCtx()
+	copyDoneChan := make(chan struct{})
+	defer close(copyDoneChan)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-copyDoneChan:
+			return
+		}
+
+		select {
+		case <-doneChan:
+			// All data has already been sent. There is nothing to abort.
+			cancelCopyCtx()
+			return
+		default:
+			w.CloseWithError(ErrCopyCanceled)
+		}
+
+		timer := time.NewTimer(copyFromCancelGracePeriod)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancelCopyCtx()
+		case <-copyDoneChan:
+		}
+	}()
+
 	go func() {
 		defer close(doneChan)
 
 		// Purposely NOT using defer w.Close(). See https://github.com/golang/go/issues/24283.
 		buf := ct.conn.wbuf
 
+		if src, ok := ct.rowSrc.(copyFromStarter); ok {
+			src.copyStart(ctx)
+		}
+
 		buf = append(buf, "PGCOPY\n\377\r\n\000"...)
 		buf = pgio.AppendInt32(buf, 0)
 		buf = pgio.AppendInt32(buf, 0)
 
 		moreRows := true
 		for moreRows {
-			var err error
-			moreRows, buf, err = ct.buildCopyBuf(buf, sd)
-			if err != nil {
-				w.CloseWithError(err)
+			var rowErr *CopyFromRowError
+			moreRows, buf, rowErr = ct.buildCopyBuf(buf, sd)
+			if rowErr != nil {
+				ct.rowErr = rowErr
+				w.CloseWithError(rowErr)
 				return
 			}
 
@@ -185,25 +626,52 @@ func (ct *copyFrom) run(ctx context.Context) (int64, error) {
 				return
 			}
 
+			if ctx.Err() != nil {
+				w.CloseWithError(ErrCopyCanceled)
+				return
+			}
+
 			if len(buf) > 0 {
-				_, err = w.Write(buf)
+				_, err := w.Write(buf)
 				if err != nil {
 					w.Close()
 					return
 				}
+
+				if counter, ok := ct.rowSrc.(copyFromByteCounter); ok {
+					counter.addBytes(len(buf))
+				}
 			}
 
 			buf = buf[:0]
 		}
 
+		if src, ok := ct.rowSrc.(copyFromDoner); ok {
+			src.copyDone()
+		}
+
 		w.Close()
 	}()
 
-	commandTag, err := ct.conn.pgConn.CopyFrom(ctx, r, fmt.Sprintf("copy %s ( %s ) from stdin binary;", quotedTableName, quotedColumnNames))
+	commandTag, err := ct.conn.pgConn.CopyFrom(copyCtx, r, fmt.Sprintf("copy %s ( %s ) from stdin binary;", quotedTableName, quotedColumnNames))
 
 	r.Close()
 	<-doneChan
 
+	if ct.rowErr != nil && err != nil {
+		ct.rowErr.copyErr = err
+		err = *ct.rowErr
+	}
+
+	if err != nil && ctx.Err() != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "57014" {
+			err = fmt.Errorf("%w: %w", ErrCopyCanceled, ctx.Err())
+		} else if copyCtx.Err() != nil {
+			err = fmt.Errorf("%w: %w", ctx.Err(), err)
+		}
+	}
+
 	if ct.conn.copyFromTracer != nil {
 		ct.conn.copyFromTracer.TraceCopyFromEnd(ctx, ct.conn, TraceCopyFromEndData{
 			CommandTag: commandTag,
@@ -214,7 +682,44 @@ func (ct *copyFrom) run(ctx context.Context) (int64, error) {
 	return commandTag.RowsAffected(), err
 }
 
-func (ct *copyFrom) buildCopyBuf(buf []byte, sd *pgconn.StatementDescription) (bool, []byte, error) {
+// checkColumns looks up the columns of the table in the system catalog and returns an error listing every column of
+// ct.columnNames that the table does not have. describeErr is wrapped by the returned error. nil is returned if the
+// lookup fails or finds no unknown columns.
+func (ct *copyFrom) checkColumns(ctx context.Context, describeErr error) error {
+	rows, _ := ct.conn.Query(ctx,
+		"select attname from pg_catalog.pg_attribute where attrelid = $1::regclass and attnum > 0 and not attisdropped order by attnum",
+		ct.tableName.Sanitize(),
+	)
+	tableColumns, err := CollectRows(rows, RowTo[string])
+	if err != nil {
+		return nil
+	}
+
+	isTableColumn := make(map[string]struct{}, len(tableColumns))
+	for _, cn := range tableColumns {
+		isTableColumn[cn] = struct{}{}
+	}
+
+	var unknownColumns []string
+	for _, cn := range ct.columnNames {
+		if _, ok := isTableColumn[cn]; !ok {
+			unknownColumns = append(unknownColumns, quoteIdentifier(cn))
+		}
+	}
+	if len(unknownColumns) == 0 {
+		return nil
+	}
+
+	quotedTableColumns := make([]string, len(tableColumns))
+	for i, cn := range tableColumns {
+		quotedTableColumns[i] = quoteIdentifier(cn)
+	}
+
+	return fmt.Errorf("table %s does not have columns %s (table columns are %s): %w",
+		ct.tableName.Sanitize(), strings.Join(unknownColumns, ", "), strings.Join(quotedTableColumns, ", "), describeErr)
+}
+
+func (ct *copyFrom) buildCopyBuf(buf []byte, sd *pgconn.StatementDescription) (bool, []byte, *CopyFromRowError) {
 	const sendBufSize = 65536 - 5 // The packet has a 5-byte header
 	lastBufLen := 0
 	largestRowLen := 0
@@ -224,19 +729,21 @@ func (ct *copyFrom) buildCopyBuf(buf []byte, sd *pgconn.StatementDescription) (b
 
 		values, err := ct.rowSrc.Values()
 		if err != nil {
-			return false, nil, err
+			return false, nil, &CopyFromRowError{RowIndex: ct.rowIdx, Err: err}
 		}
 		if len(values) != len(ct.columnNames) {
-			return false, nil, fmt.Errorf("expected %d values, got %d values", len(ct.columnNames), len(values))
+			err := fmt.Errorf("expected %d values, got %d values", len(ct.columnNames), len(values))
+			return false, nil, &CopyFromRowError{RowIndex: ct.rowIdx, Err: err}
 		}
 
 		buf = pgio.AppendInt16(buf, int16(len(ct.columnNames)))
 		for i, val := range values {
 			buf, err = encodeCopyValue(ct.conn.typeMap, buf, sd.Fields[i].DataTypeOID, val)
 			if err != nil {
-				return false, nil, err
+				return false, nil, &CopyFromRowError{RowIndex: ct.rowIdx, Column: ct.columnNames[i], Err: err}
 			}
 		}
+		ct.rowIdx++
 
 		rowLen := len(buf) - lastBufLen
 		if rowLen > largestRowLen {
@@ -262,7 +769,33 @@ func (ct *copyFrom) buildCopyBuf(buf []byte, sd *pgconn.StatementDescription) (b
 //
 // Even though enum types appear to be strings they still must be registered to use with CopyFrom. This can be done with
 // Conn.LoadType and pgtype.Map.RegisterType.
+//
+// columnNames may be nil when rowSrc was created by CopyFromStructs or CopyFromMaps. The columns are then derived from
+// the struct type or the map keys. If any column does not exist in the table, the returned error lists all unknown
+// columns. No data is sent in this case.
+//
+// If the copy fails a *pgconn.PgError is returned. When the server rejects a row (e.g. a constraint violation) its
+// Where field identifies the row as a line number (e.g. "COPY foo, line 3"). Line n is the source row with index n-1.
+// When a row cannot be read from rowSrc or encoded, the copy is aborted and a CopyFromRowError that identifies the
+// source row and the column is returned.
+//
+// If ctx is canceled while the copy is in progress, the copy is aborted and the connection remains usable. The
+// returned error wraps ErrCopyCanceled and the context error. If the server does not acknowledge the aborted copy
+// promptly the connection is closed as with any other canceled query.
 func (c *Conn) CopyFrom(ctx context.Context, tableName Identifier, columnNames []string, rowSrc CopyFromSource) (int64, error) {
+	if c.config.ReadOnly {
+		return 0, fmt.Errorf("%w: cannot execute COPY FROM", ErrReadOnly)
+	}
+
+	ctx, cancel := c.withDefaultQueryTimeout(ctx)
+	defer cancel()
+
+	if columnNames == nil {
+		if src, ok := rowSrc.(copyFromColumnNamer); ok {
+			columnNames = src.columnNames()
+		}
+	}
+
 	ct := &copyFrom{
 		conn:          c,
 		tableName:     tableName,
@@ -274,3 +807,49 @@ func (c *Conn) CopyFrom(ctx context.Context, tableName Identifier, columnNames [
 
 	return ct.run(ctx)
 }
+
+// CopyFromReader uses the PostgreSQL copy protocol to pipe the data read from r directly into tableName. The data must
+// already be in format. It is sent as is without any client-side parsing or encoding. This is useful to load files
+// produced by other systems. It returns the number of rows copied. If columnNames is nil the data must contain all
+// columns of the table in order.
+//
+// Note: context cancellation will only interrupt operations on the underlying PostgreSQL network connection. Reads on r
+// could still block.
+func (c *Conn) CopyFromReader(ctx context.Context, tableName Identifier, columnNames []string, r io.Reader, format CopyFormat) (int64, error) {
+	switch format {
+	case CopyFormatText, CopyFormatCSV, CopyFormatBinary:
+	default:
+		return 0, fmt.Errorf("unknown CopyFormat: %s", format)
+	}
+
+	if c.config.ReadOnly {
+		return 0, fmt.Errorf("%w: cannot execute COPY FROM", ErrReadOnly)
+	}
+
+	ctx, cancel := c.withDefaultQueryTimeout(ctx)
+	defer cancel()
+
+	sb := &strings.Builder{}
+	sb.WriteString("copy ")
+	sb.WriteString(tableName.Sanitize())
+	if columnNames != nil {
+		sb.WriteString(" ( ")
+		for i, cn := range columnNames {
+			if i != 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(quoteIdentifier(cn))
+		}
+		sb.WriteString(" )")
+	}
+	sb.WriteString(" from stdin with (format ")
+	sb.WriteString(string(format))
+	sb.WriteString(")")
+
+	ct, err := c.pgConn.CopyFrom(ctx, r, sb.String())
+	if err != nil {
+		return 0, err
+	}
+
+	return ct.RowsAffected(), nil
+}
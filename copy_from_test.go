@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -198,6 +199,70 @@ func TestConnCopyFromSmall(t *testing.T) {
 	ensureConnValid(t, conn)
 }
 
+func TestConnCopyFromStructs(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	mustExec(t, conn, `create temporary table foo(
+		id int4,
+		name text,
+		author_id int4,
+		author_name text
+	)`)
+
+	type author struct {
+		ID   int32  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	type book struct {
+		ID     int32
+		Title  string `db:"name"`
+		Ignore string `db:"-"`
+		author `db:"author"`
+	}
+
+	inputRows := []book{
+		{ID: 1, Title: "Dune", author: author{ID: 10, Name: "Frank Herbert"}},
+		{ID: 2, Title: "Emma", author: author{ID: 20, Name: "Jane Austen"}},
+	}
+
+	copyCount, err := conn.CopyFrom(ctx, pgx.Identifier{"foo"}, nil, pgx.CopyFromStructs(inputRows))
+	require.NoError(t, err)
+	require.EqualValues(t, len(inputRows), copyCount)
+
+	rows, _ := conn.Query(ctx, "select * from foo order by id")
+	outputRows, err := pgx.CollectRows(rows, pgx.RowToStructByName[book])
+	require.NoError(t, err)
+	require.Equal(t, inputRows, outputRows)
+
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"foo"}, []string{"id"}, pgx.CopyFromStructs([]int32{1}))
+	require.Error(t, err)
+
+	// Untagged fields are copied to the field name folded to lower case.
+	mustExec(t, conn, `create temporary table bar(id int4, createdat text)`)
+
+	type event struct {
+		ID        int32
+		CreatedAt string
+	}
+
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"bar"}, nil, pgx.CopyFromStructs([]event{{ID: 1, CreatedAt: "today"}}))
+	require.NoError(t, err)
+
+	var createdAt string
+	err = conn.QueryRow(ctx, "select createdat from bar where id = 1").Scan(&createdAt)
+	require.NoError(t, err)
+	require.Equal(t, "today", createdAt)
+
+	ensureConnValid(t, conn)
+}
+
 func TestConnCopyFromSliceSmall(t *testing.T) {
 	t.Parallel()
 
@@ -892,3 +957,368 @@ func TestCopyFromFunc(t *testing.T) {
 
 	ensureConnValid(t, conn)
 }
+
+func TestConnCopyFromReader(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	mustExec(t, conn, `create temporary table foo(a int4, b text)`)
+
+	copyCount, err := conn.CopyFromReader(ctx, pgx.Identifier{"foo"}, []string{"a", "b"}, strings.NewReader("1,\"x,y\"\n2,\n"), pgx.CopyFormatCSV)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, copyCount)
+
+	copyCount, err = conn.CopyFromReader(ctx, pgx.Identifier{"foo"}, nil, strings.NewReader("3\tz\n"), pgx.CopyFormatText)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, copyCount)
+
+	_, err = conn.CopyFromReader(ctx, pgx.Identifier{"foo"}, nil, strings.NewReader("not a number\tz\n"), pgx.CopyFormatText)
+	require.Error(t, err)
+
+	rows, _ := conn.Query(ctx, "select a, b from foo order by a")
+	type row struct {
+		A int32
+		B *string
+	}
+	outputRows, err := pgx.CollectRows(rows, pgx.RowToStructByPos[row])
+	require.NoError(t, err)
+	require.Len(t, outputRows, 3)
+	require.Equal(t, "x,y", *outputRows[0].B)
+	require.Nil(t, outputRows[1].B)
+	require.Equal(t, "z", *outputRows[2].B)
+
+	ensureConnValid(t, conn)
+}
+
+func TestConnCopyFromChan(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	mustExec(t, conn, `create temporary table foo(a int4, b text)`)
+
+	ch := make(chan []any)
+	go func() {
+		defer close(ch)
+		for i := 0; i < 1000; i++ {
+			select {
+			case ch <- []any{int32(i), fmt.Sprint(i)}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	copyCount, err := conn.CopyFrom(ctx, pgx.Identifier{"foo"}, []string{"a", "b"}, pgx.CopyFromChan(ch))
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, copyCount)
+
+	var n int64
+	var sum int64
+	err = conn.QueryRow(ctx, "select count(*), sum(a) from foo where a::text = b").Scan(&n, &sum)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, n)
+	require.EqualValues(t, 999*1000/2, sum)
+
+	ensureConnValid(t, conn)
+}
+
+func TestConnCopyFromWithProgress(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	mustExec(t, conn, `create temporary table foo(a int4)`)
+
+	inputRows := make([][]any, 1000)
+	for i := range inputRows {
+		inputRows[i] = []any{int32(i)}
+	}
+
+	var reports []pgx.CopyProgress
+	src := pgx.CopyFromWithProgress(pgx.CopyFromRows(inputRows), 300, 0, func(p pgx.CopyProgress) {
+		reports = append(reports, p)
+	})
+	copyCount, err := conn.CopyFrom(ctx, pgx.Identifier{"foo"}, []string{"a"}, src)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, copyCount)
+
+	require.Len(t, reports, 4)
+	for i, p := range reports[:3] {
+		require.EqualValues(t, (i+1)*300, p.Rows)
+	}
+	require.EqualValues(t, 1000, reports[3].Rows)
+	require.Greater(t, reports[3].Bytes, int64(1000*4))
+
+	reports = nil
+	src = pgx.CopyFromWithProgress(pgx.CopyFromRows(inputRows), 0, 1, func(p pgx.CopyProgress) {
+		reports = append(reports, p)
+	})
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"foo"}, []string{"a"}, src)
+	require.NoError(t, err)
+	require.NotEmpty(t, reports)
+	require.EqualValues(t, 1000, reports[len(reports)-1].Rows)
+	require.Greater(t, reports[len(reports)-1].Bytes, int64(1000*4))
+
+	ensureConnValid(t, conn)
+}
+
+func TestConnCopyFromErrorIdentifiesRow(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	pgxtest.SkipCockroachDB(t, conn, "Server reports COPY errors differently")
+
+	mustExec(t, conn, `create temporary table foo(a int4, b text not null)`)
+
+	inputRows := [][]any{
+		{int32(1), "abc"},
+		{int32(2), "def"},
+		{struct{}{}, "ghi"},
+	}
+	_, err := conn.CopyFrom(ctx, pgx.Identifier{"foo"}, []string{"a", "b"}, pgx.CopyFromRows(inputRows))
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	require.Contains(t, pgErr.Message, "source row 2, column a")
+	var rowErr pgx.CopyFromRowError
+	require.ErrorAs(t, err, &rowErr)
+	require.EqualValues(t, 2, rowErr.RowIndex)
+	require.Equal(t, "a", rowErr.Column)
+	require.Error(t, rowErr.Err)
+	require.EqualError(t, err, "source row 2, column a: "+rowErr.Err.Error())
+
+	inputRows = [][]any{
+		{int32(1), "abc"},
+		{int32(2)},
+	}
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"foo"}, []string{"a", "b"}, pgx.CopyFromRows(inputRows))
+	require.ErrorAs(t, err, &pgErr)
+	require.ErrorAs(t, err, &rowErr)
+	require.EqualValues(t, 1, rowErr.RowIndex)
+	require.Empty(t, rowErr.Column)
+	require.EqualError(t, err, "source row 1: expected 2 values, got 1 values")
+
+	inputRows = [][]any{
+		{int32(1), "abc"},
+		{int32(2), nil},
+	}
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"foo"}, []string{"a", "b"}, pgx.CopyFromRows(inputRows))
+	require.ErrorAs(t, err, &pgErr)
+	require.Equal(t, "23502", pgErr.Code)
+	require.Contains(t, pgErr.Where, "line 2")
+
+	ensureConnValid(t, conn)
+}
+
+func TestCopyUpsert(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	mustExec(t, conn, `create temporary table foo(
+		id int4 primary key,
+		name text not null,
+		kind text not null default 'book'
+	)`)
+	mustExec(t, conn, `insert into foo (id, name) values (1, 'Dune'), (2, 'Emma')`)
+
+	inputRows := [][]any{
+		{int32(2), "Persuasion"},
+		{int32(3), "Ulysses"},
+	}
+
+	rowsAffected, err := pgx.CopyUpsert(ctx, conn, pgx.Identifier{"foo"}, []string{"id", "name"}, []string{"id"}, pgx.CopyFromRows(inputRows))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, rowsAffected)
+
+	type book struct {
+		ID   int32
+		Name string
+		Kind string
+	}
+
+	rows, _ := conn.Query(ctx, "select id, name, kind from foo order by id")
+	outputRows, err := pgx.CollectRows(rows, pgx.RowToStructByPos[book])
+	require.NoError(t, err)
+	require.Equal(t, []book{
+		{ID: 1, Name: "Dune", Kind: "book"},
+		{ID: 2, Name: "Persuasion", Kind: "book"},
+		{ID: 3, Name: "Ulysses", Kind: "book"},
+	}, outputRows)
+
+	// The temporary table is dropped so CopyUpsert can be called again in the same transaction.
+	tx, err := conn.Begin(ctx)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		rowsAffected, err = pgx.CopyUpsert(ctx, tx, pgx.Identifier{"foo"}, []string{"id"}, []string{"id"}, pgx.CopyFromRows([][]any{{int32(3)}, {int32(4)}}))
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 0, rowsAffected)
+	require.NoError(t, tx.Commit(ctx))
+
+	var count int
+	err = conn.QueryRow(ctx, "select count(*) from foo").Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+
+	_, err = pgx.CopyUpsert(ctx, conn, pgx.Identifier{"foo"}, []string{"id", "name"}, nil, pgx.CopyFromRows(inputRows))
+	require.Error(t, err)
+
+	ensureConnValid(t, conn)
+}
+
+func TestConnCopyFromContextCancel(t *testing.T) {
+	t.Parallel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	mustExec(t, conn, `create temporary table foo(a int4)`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan []any)
+	go func() {
+		defer close(ch)
+		for i := 0; ; i++ {
+			if i == 10 {
+				cancel()
+			}
+			select {
+			case ch <- []any{int32(i)}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	copyCount, err := conn.CopyFrom(ctx, pgx.Identifier{"foo"}, []string{"a"}, pgx.CopyFromChan(ch))
+	require.ErrorIs(t, err, pgx.ErrCopyCanceled)
+	require.ErrorIs(t, err, context.Canceled)
+	require.EqualValues(t, 0, copyCount)
+	require.False(t, conn.IsClosed())
+
+	var count int
+	err = conn.QueryRow(context.Background(), "select count(*) from foo").Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	ensureConnValid(t, conn)
+}
+
+func TestConnCopyFromMaps(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	mustExec(t, conn, `create temporary table foo(a int4, b text)`)
+
+	inputRows := []map[string]any{
+		{"a": int32(1), "b": "foo"},
+		{"a": int32(2)},
+	}
+
+	copyCount, err := conn.CopyFrom(ctx, pgx.Identifier{"foo"}, nil, pgx.CopyFromMaps(inputRows))
+	require.NoError(t, err)
+	require.EqualValues(t, len(inputRows), copyCount)
+
+	rows, _ := conn.Query(ctx, "select a, b from foo order by a")
+	outputRows, err := pgx.CollectRows(rows, pgx.RowToMap)
+	require.NoError(t, err)
+	require.Equal(t, []map[string]any{
+		{"a": int32(1), "b": "foo"},
+		{"a": int32(2), "b": nil},
+	}, outputRows)
+
+	ensureConnValid(t, conn)
+}
+
+func TestConnCopyFromUnknownColumns(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	mustExec(t, conn, `create temporary table foo(a int4, b text)`)
+
+	inputRows := []map[string]any{
+		{"a": int32(1), "c": "foo", "d": "bar"},
+	}
+
+	copyCount, err := conn.CopyFrom(ctx, pgx.Identifier{"foo"}, nil, pgx.CopyFromMaps(inputRows))
+	require.ErrorContains(t, err, `table "foo" does not have columns "c", "d" (table columns are "a", "b")`)
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	require.EqualValues(t, 0, copyCount)
+
+	ensureConnValid(t, conn)
+}
+
+func TestConnCopyFromWithRateLimit(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	mustExec(t, conn, `create temporary table foo(a int4)`)
+
+	inputRows := [][]any{}
+	for i := 0; i < 20; i++ {
+		inputRows = append(inputRows, []any{int32(i)})
+	}
+
+	var lastProgress pgx.CopyProgress
+	src := pgx.CopyFromWithProgress(pgx.CopyFromWithRateLimit(pgx.CopyFromRows(inputRows), 100, 0), 0, 1, func(p pgx.CopyProgress) {
+		lastProgress = p
+	})
+
+	start := time.Now()
+	copyCount, err := conn.CopyFrom(ctx, pgx.Identifier{"foo"}, []string{"a"}, src)
+	require.NoError(t, err)
+	require.EqualValues(t, len(inputRows), copyCount)
+	require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	require.EqualValues(t, len(inputRows), lastProgress.Rows)
+
+	// A delay of the rate limit ends when the context is canceled.
+	cancelCtx, cancelCopy := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancelCopy()
+	start = time.Now()
+	_, err = conn.CopyFrom(cancelCtx, pgx.Identifier{"foo"}, []string{"a"}, pgx.CopyFromWithRateLimit(pgx.CopyFromRows(inputRows), 1, 0))
+	require.ErrorIs(t, err, pgx.ErrCopyCanceled)
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	ensureConnValid(t, conn)
+}
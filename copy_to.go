@@ -0,0 +1,44 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// CopyFormat is a data format of the PostgreSQL COPY command.
+type CopyFormat string
+
+const (
+	CopyFormatText   CopyFormat = "text"
+	CopyFormatCSV    CopyFormat = "csv"
+	CopyFormatBinary CopyFormat = "binary"
+)
+
+// CopyTo uses the PostgreSQL copy protocol to stream the results of the query sql to w in format. It executes
+// COPY (sql) TO STDOUT and returns the number of rows copied. w receives the data exactly as sent by the server. This is
+// usually the fastest way to export a large result set.
+//
+// sql must be a single SELECT, VALUES, INSERT ... RETURNING, UPDATE ... RETURNING, or DELETE ... RETURNING statement
+// without a trailing semicolon. It cannot have arguments.
+func (c *Conn) CopyTo(ctx context.Context, w io.Writer, sql string, format CopyFormat) (int64, error) {
+	switch format {
+	case CopyFormatText, CopyFormatCSV, CopyFormatBinary:
+	default:
+		return 0, fmt.Errorf("unknown CopyFormat: %s", format)
+	}
+
+	if err := c.checkReadOnly(sql); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := c.withDefaultQueryTimeout(ctx)
+	defer cancel()
+
+	ct, err := c.pgConn.CopyTo(ctx, w, fmt.Sprintf("copy (%s) to stdout with (format %s)", sql, format))
+	if err != nil {
+		return 0, err
+	}
+
+	return ct.RowsAffected(), nil
+}
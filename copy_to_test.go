@@ -0,0 +1,42 @@
+package pgx_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnCopyTo(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	var buf bytes.Buffer
+	n, err := conn.CopyTo(ctx, &buf, "select n, 'a,b' from generate_series(1, 3) n", pgx.CopyFormatCSV)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, n)
+	require.Equal(t, "1,\"a,b\"\n2,\"a,b\"\n3,\"a,b\"\n", buf.String())
+
+	buf.Reset()
+	n, err = conn.CopyTo(ctx, &buf, "select n, null from generate_series(1, 2) n", pgx.CopyFormatText)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+	require.Equal(t, "1\t\\N\n2\t\\N\n", buf.String())
+
+	_, err = conn.CopyTo(ctx, &buf, "select 1", pgx.CopyFormat("xml"))
+	require.Error(t, err)
+
+	_, err = conn.CopyTo(ctx, &buf, "select 1/0", pgx.CopyFormatText)
+	require.Error(t, err)
+
+	ensureConnValid(t, conn)
+}
@@ -0,0 +1,113 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// copyUpsertTempTable is the name of the temporary table used by CopyUpsert.
+const copyUpsertTempTable = "pgx_copy_upsert"
+
+// CopyUpsert inserts the rows of rowSrc into tableName updating any rows that already exist. PostgreSQL COPY cannot
+// handle conflicts so CopyUpsert copies the rows into a temporary table and then merges them into tableName with
+// INSERT ... ON CONFLICT (conflictColumns) DO UPDATE. If every column is a conflict column the conflicting rows are left
+// unchanged. It returns the number of rows inserted or updated.
+//
+// All of this happens in a single transaction started with db.Begin. db is typically a *Conn, Tx, or *pgxpool.Pool. If
+// db is a Tx a savepoint is used instead. conflictColumns must match a unique index or constraint of tableName.
+//
+// If columnNames is nil and rowSrc was created by CopyFromStructs or CopyFromMaps, the column names are derived from
+// the source.
+func CopyUpsert(
+	ctx context.Context,
+	db interface {
+		Begin(ctx context.Context) (Tx, error)
+	},
+	tableName Identifier,
+	columnNames []string,
+	conflictColumns []string,
+	rowSrc CopyFromSource,
+) (rowsAffected int64, err error) {
+	if columnNames == nil {
+		if cn, ok := rowSrc.(copyFromColumnNamer); ok {
+			columnNames = cn.columnNames()
+		}
+	}
+	if len(columnNames) == 0 {
+		return 0, errors.New("CopyUpsert requires column names")
+	}
+	if len(conflictColumns) == 0 {
+		return 0, errors.New("CopyUpsert requires conflict columns")
+	}
+
+	quotedColumns := make([]string, len(columnNames))
+	for i, cn := range columnNames {
+		quotedColumns[i] = quoteIdentifier(cn)
+	}
+	columnList := strings.Join(quotedColumns, ", ")
+
+	isConflictColumn := make(map[string]struct{}, len(conflictColumns))
+	quotedConflictColumns := make([]string, len(conflictColumns))
+	for i, cn := range conflictColumns {
+		isConflictColumn[cn] = struct{}{}
+		quotedConflictColumns[i] = quoteIdentifier(cn)
+	}
+
+	var assignments []string
+	for i, cn := range columnNames {
+		if _, ok := isConflictColumn[cn]; !ok {
+			assignments = append(assignments, quotedColumns[i]+" = excluded."+quotedColumns[i])
+		}
+	}
+
+	tmpTable := quoteIdentifier(copyUpsertTempTable)
+
+	err = BeginFunc(ctx, db, func(tx Tx) error {
+		// CREATE TABLE AS copies only the column types. LIKE would also copy NOT NULL constraints which would reject rows
+		// that omit a column with a default.
+		_, err := tx.Exec(ctx, "create temporary table "+tmpTable+" on commit drop as select "+columnList+" from "+
+			tableName.Sanitize()+" with no data")
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CopyFrom(ctx, Identifier{copyUpsertTempTable}, columnNames, rowSrc)
+		if err != nil {
+			return err
+		}
+
+		var sb strings.Builder
+		sb.WriteString("insert into ")
+		sb.WriteString(tableName.Sanitize())
+		sb.WriteString(" (")
+		sb.WriteString(columnList)
+		sb.WriteString(") select ")
+		sb.WriteString(columnList)
+		sb.WriteString(" from ")
+		sb.WriteString(tmpTable)
+		sb.WriteString(" on conflict (")
+		sb.WriteString(strings.Join(quotedConflictColumns, ", "))
+		if len(assignments) == 0 {
+			sb.WriteString(") do nothing")
+		} else {
+			sb.WriteString(") do update set ")
+			sb.WriteString(strings.Join(assignments, ", "))
+		}
+
+		ct, err := tx.Exec(ctx, sb.String())
+		if err != nil {
+			return err
+		}
+		rowsAffected = ct.RowsAffected()
+
+		// Drop the table explicitly in case db is a Tx and CopyUpsert is called again before the outer transaction ends.
+		_, err = tx.Exec(ctx, "drop table "+tmpTable)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, nil
+}
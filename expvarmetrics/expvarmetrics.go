@@ -0,0 +1,189 @@
+// Package expvarmetrics publishes pgxpool and query metrics with the standard library expvar package.
+//
+// Pool metrics are read from pgxpool.Stat. Acquire and query latencies are recorded by a Tracer which must be set as the
+// Tracer of the pool's ConnConfig before the pool is created.
+//
+//	tracer := expvarmetrics.NewTracer()
+//	config.ConnConfig.Tracer = tracer
+//	pool, err := pgxpool.NewWithConfig(ctx, config)
+//	// ...
+//	expvarmetrics.Publish("db", pool, tracer)
+package expvarmetrics
+
+import (
+	"context"
+	"expvar"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Metrics is a snapshot of the metrics of a pool. It is the value published by Publish.
+type Metrics struct {
+	TotalConns        int32 `json:"total_conns"`
+	IdleConns         int32 `json:"idle_conns"`
+	AcquiredConns     int32 `json:"acquired_conns"`
+	ConstructingConns int32 `json:"constructing_conns"`
+	MaxConns          int32 `json:"max_conns"`
+
+	AcquireCount            int64   `json:"acquire_count"`
+	AcquireSeconds          float64 `json:"acquire_seconds"`
+	EmptyAcquireCount       int64   `json:"empty_acquire_count"`
+	CanceledAcquireCount    int64   `json:"canceled_acquire_count"`
+	NewConnsCount           int64   `json:"new_conns_count"`
+	MaxLifetimeDestroyCount int64   `json:"max_lifetime_destroy_count"`
+	MaxIdleDestroyCount     int64   `json:"max_idle_destroy_count"`
+
+	// Acquire is the latency of Acquire including failed acquires. It is nil if there is no Tracer.
+	Acquire *Latency `json:"acquire,omitempty"`
+
+	// Queries is the latency of queries by the command of their command tag (e.g. SELECT, INSERT). Failed queries are
+	// recorded as ERROR and queries without a command tag as UNKNOWN. It is nil if there is no Tracer.
+	Queries map[string]Latency `json:"queries,omitempty"`
+}
+
+// Latency summarizes the duration of a traced operation.
+type Latency struct {
+	Count      int64   `json:"count"`
+	ErrorCount int64   `json:"error_count"`
+	Seconds    float64 `json:"seconds"`     // total duration of all operations
+	MaxSeconds float64 `json:"max_seconds"` // duration of the slowest operation
+}
+
+func (l *Latency) record(d time.Duration, err error) {
+	l.Count++
+	if err != nil {
+		l.ErrorCount++
+	}
+	l.Seconds += d.Seconds()
+	if d.Seconds() > l.MaxSeconds {
+		l.MaxSeconds = d.Seconds()
+	}
+}
+
+// Tracer records the latency of acquires and queries. It implements pgx.QueryTracer and pgxpool.AcquireTracer.
+type Tracer struct {
+	mux     sync.Mutex
+	acquire Latency
+	queries map[string]*Latency
+}
+
+// NewTracer returns a new Tracer.
+func NewTracer() *Tracer {
+	return &Tracer{queries: make(map[string]*Latency)}
+}
+
+// Keys of Metrics.Queries for queries that do not have a command tag.
+const (
+	errorCommand   = "ERROR"
+	unknownCommand = "UNKNOWN"
+)
+
+type ctxKey int
+
+const (
+	_ ctxKey = iota
+	queryCtxKey
+	acquireCtxKey
+)
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (t *Tracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryCtxKey, time.Now())
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (t *Tracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	startTime, ok := ctx.Value(queryCtxKey).(time.Time)
+	if !ok {
+		return
+	}
+	d := time.Since(startTime)
+
+	// The SQL is not used as the key as it would let arbitrary text create keys.
+	command := firstWord(data.CommandTag.String())
+	if data.Err != nil {
+		command = errorCommand
+	} else if command == "" {
+		command = unknownCommand
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	l, ok := t.queries[command]
+	if !ok {
+		l = &Latency{}
+		t.queries[command] = l
+	}
+	l.record(d, data.Err)
+}
+
+// TraceAcquireStart implements pgxpool.AcquireTracer.
+func (t *Tracer) TraceAcquireStart(ctx context.Context, pool *pgxpool.Pool, data pgxpool.TraceAcquireStartData) context.Context {
+	return context.WithValue(ctx, acquireCtxKey, time.Now())
+}
+
+// TraceAcquireEnd implements pgxpool.AcquireTracer.
+func (t *Tracer) TraceAcquireEnd(ctx context.Context, pool *pgxpool.Pool, data pgxpool.TraceAcquireEndData) {
+	startTime, ok := ctx.Value(acquireCtxKey).(time.Time)
+	if !ok {
+		return
+	}
+	d := time.Since(startTime)
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.acquire.record(d, data.Err)
+}
+
+func firstWord(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// Snapshot returns the current metrics of pool. tracer may be nil.
+func Snapshot(pool *pgxpool.Pool, tracer *Tracer) *Metrics {
+	stat := pool.Stat()
+	m := &Metrics{
+		TotalConns:        stat.TotalConns(),
+		IdleConns:         stat.IdleConns(),
+		AcquiredConns:     stat.AcquiredConns(),
+		ConstructingConns: stat.ConstructingConns(),
+		MaxConns:          stat.MaxConns(),
+
+		AcquireCount:            stat.AcquireCount(),
+		AcquireSeconds:          stat.AcquireDuration().Seconds(),
+		EmptyAcquireCount:       stat.EmptyAcquireCount(),
+		CanceledAcquireCount:    stat.CanceledAcquireCount(),
+		NewConnsCount:           stat.NewConnsCount(),
+		MaxLifetimeDestroyCount: stat.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     stat.MaxIdleDestroyCount(),
+	}
+
+	if tracer != nil {
+		tracer.mux.Lock()
+		acquire := tracer.acquire
+		m.Acquire = &acquire
+		m.Queries = make(map[string]Latency, len(tracer.queries))
+		for command, l := range tracer.queries {
+			m.Queries[command] = *l
+		}
+		tracer.mux.Unlock()
+	}
+
+	return m
+}
+
+// Publish publishes the metrics of pool as the expvar variable name. The metrics are computed by Snapshot each time the
+// variable is read. tracer may be nil. Like expvar.Publish, Publish panics if name is already registered.
+func Publish(name string, pool *pgxpool.Pool, tracer *Tracer) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return Snapshot(pool, tracer)
+	}))
+}
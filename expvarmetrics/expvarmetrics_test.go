@@ -0,0 +1,74 @@
+package expvarmetrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/expvarmetrics"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	tracer := expvarmetrics.NewTracer()
+	config, err := pgxpool.ParseConfig("pool_max_conns=7")
+	require.NoError(t, err)
+	config.ConnConfig.Tracer = tracer
+
+	// No connection is established until the pool is used.
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "select 1"})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+	qctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: " insert into t values (1)"})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+	qctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: ""})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{})
+
+	actx := tracer.TraceAcquireStart(ctx, pool, pgxpool.TraceAcquireStartData{})
+	tracer.TraceAcquireEnd(actx, pool, pgxpool.TraceAcquireEndData{})
+
+	expvarmetrics.Publish("expvarmetrics_test_pool", pool, tracer)
+
+	var m expvarmetrics.Metrics
+	err = json.Unmarshal([]byte(expvar.Get("expvarmetrics_test_pool").String()), &m)
+	require.NoError(t, err)
+
+	require.EqualValues(t, 7, m.MaxConns)
+	require.EqualValues(t, 0, m.TotalConns)
+	require.NotNil(t, m.Acquire)
+	require.EqualValues(t, 1, m.Acquire.Count)
+	require.EqualValues(t, 0, m.Acquire.ErrorCount)
+	require.Len(t, m.Queries, 3)
+	require.EqualValues(t, 1, m.Queries["SELECT"].Count)
+	require.EqualValues(t, 0, m.Queries["SELECT"].ErrorCount)
+	require.EqualValues(t, 1, m.Queries["ERROR"].Count)
+	require.EqualValues(t, 1, m.Queries["ERROR"].ErrorCount)
+	require.EqualValues(t, 1, m.Queries["UNKNOWN"].Count)
+	require.EqualValues(t, 0, m.Queries["UNKNOWN"].ErrorCount)
+}
+
+func TestSnapshotWithoutTracer(t *testing.T) {
+	t.Parallel()
+
+	config, err := pgxpool.ParseConfig("")
+	require.NoError(t, err)
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	m := expvarmetrics.Snapshot(pool, nil)
+	require.Nil(t, m.Acquire)
+	require.Nil(t, m.Queries)
+}
@@ -33,7 +33,7 @@ func (eqb *ExtendedQueryBuilder) Build(m *pgtype.Map, sd *pgconn.StatementDescri
 	}
 
 	if len(sd.ParamOIDs) != len(args) {
-		return fmt.Errorf("mismatched param and argument count")
+		return fmt.Errorf("mismatched param and argument count: expected %d arguments, got %d for %q", len(sd.ParamOIDs), len(args), sd.SQL)
 	}
 
 	for i := range args {
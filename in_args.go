@@ -0,0 +1,76 @@
+package pgx
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/internal/sanitize"
+)
+
+// InArgs can be used as the first argument to a query method. It contains the positional arguments for the query. Any
+// placeholder that is the only element of an IN list and whose argument is a slice or array is rewritten to the
+// equivalent ANY or ALL array comparison. `IN ($n)` becomes `= any($n)` and `NOT IN ($n)` becomes `<> all($n)`. The
+// slice is sent as a single array argument so the SQL does not depend on the length of the slice and an empty slice
+// behaves as an empty IN list would.
+//
+// For example, the following two queries are equivalent:
+//
+//	conn.Query(ctx, "select * from widgets where id in ($1) and status = $2", pgx.InArgs{[]int32{1, 2, 3}, "active"})
+//	conn.Query(ctx, "select * from widgets where id = any($1) and status = $2", []int32{1, 2, 3}, "active")
+//
+// []byte arguments are not treated as slices. Placeholders that are not the only element of an IN list and arguments
+// that are not slices or arrays are left unchanged.
+type InArgs []any
+
+var inListPrefixRegexp = regexp.MustCompile(`(?i)(\bnot\s+)?\bin\s*\(\s*$`)
+var inListSuffixRegexp = regexp.MustCompile(`^\s*\)`)
+
+// RewriteQuery implements the QueryRewriter interface.
+func (ia InArgs) RewriteQuery(ctx context.Context, conn *Conn, sql string, args []any) (newSQL string, newArgs []any, err error) {
+	query, err := sanitize.NewQuery(sql)
+	if err != nil {
+		return "", nil, err
+	}
+
+	buf := make([]byte, 0, len(sql))
+	for i, part := range query.Parts {
+		switch part := part.(type) {
+		case string:
+			buf = append(buf, part...)
+		case int:
+			if part > 0 && part <= len(ia) && isInListSliceArg(ia[part-1]) && i+1 < len(query.Parts) {
+				if next, ok := query.Parts[i+1].(string); ok && inListSuffixRegexp.MatchString(next) {
+					if loc := inListPrefixRegexp.FindSubmatchIndex(buf); loc != nil {
+						isNot := loc[2] != -1
+						buf = buf[:loc[0]]
+						if isNot {
+							buf = append(buf, "<> all("...)
+						} else {
+							buf = append(buf, "= any("...)
+						}
+					}
+				}
+			}
+			buf = append(buf, '$')
+			buf = strconv.AppendInt(buf, int64(part), 10)
+		}
+	}
+
+	return string(buf), ia, nil
+}
+
+func isInListSliceArg(arg any) bool {
+	if arg == nil {
+		return false
+	}
+
+	typ := reflect.TypeOf(arg)
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		return typ.Elem().Kind() != reflect.Uint8
+	default:
+		return false
+	}
+}
@@ -0,0 +1,82 @@
+package pgx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInArgsRewriteQuery(t *testing.T) {
+	t.Parallel()
+
+	for i, tt := range []struct {
+		sql         string
+		inArgs      pgx.InArgs
+		expectedSQL string
+	}{
+		{
+			sql:         "select * from t where id in ($1)",
+			inArgs:      pgx.InArgs{[]int32{1, 2, 3}},
+			expectedSQL: "select * from t where id = any($1)",
+		},
+		{
+			sql:         "select * from t where id NOT IN( $1 ) and name = $2",
+			inArgs:      pgx.InArgs{[]int32{1, 2, 3}, "foo"},
+			expectedSQL: "select * from t where id <> all($1 ) and name = $2",
+		},
+		{
+			sql:         "select * from t where id in ($1) and name in ($2)",
+			inArgs:      pgx.InArgs{int32(1), []string{"foo", "bar"}},
+			expectedSQL: "select * from t where id in ($1) and name = any($2)",
+		},
+		{
+			sql:         "select * from t where id in ($1, $2)",
+			inArgs:      pgx.InArgs{[]int32{1}, []int32{2}},
+			expectedSQL: "select * from t where id in ($1, $2)",
+		},
+		{
+			sql:         "select * from t where data in ($1)",
+			inArgs:      pgx.InArgs{[]byte("foo")},
+			expectedSQL: "select * from t where data in ($1)",
+		},
+		{
+			sql:         "select 'in ($1)', * from t where join_id = $1",
+			inArgs:      pgx.InArgs{[]int32{1}},
+			expectedSQL: "select 'in ($1)', * from t where join_id = $1",
+		},
+		{
+			sql:         "select * from t where min ($1)",
+			inArgs:      pgx.InArgs{[]int32{1}},
+			expectedSQL: "select * from t where min ($1)",
+		},
+	} {
+		sql, args, err := tt.inArgs.RewriteQuery(context.Background(), nil, tt.sql, nil)
+		require.NoError(t, err)
+		assert.Equalf(t, tt.expectedSQL, sql, "%d", i)
+		assert.Equalf(t, []any(tt.inArgs), args, "%d", i)
+	}
+}
+
+func TestInArgsQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pgxtest.RunWithQueryExecModes(ctx, t, defaultConnTestRunner, nil, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		rows, _ := conn.Query(ctx, "select n from generate_series(1, 5) n where n in ($1) order by n", pgx.InArgs{[]int32{2, 4, 6}})
+		numbers, err := pgx.CollectRows(rows, pgx.RowTo[int32])
+		require.NoError(t, err)
+		require.Equal(t, []int32{2, 4}, numbers)
+
+		rows, _ = conn.Query(ctx, "select n from generate_series(1, 5) n where n not in ($1) and n < $2 order by n", pgx.InArgs{[]int32{}, int32(3)})
+		numbers, err = pgx.CollectRows(rows, pgx.RowTo[int32])
+		require.NoError(t, err)
+		require.Equal(t, []int32{1, 2}, numbers)
+	})
+}
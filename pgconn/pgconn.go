@@ -70,6 +70,13 @@ type NoticeHandler func(*PgConn, *Notice)
 // notice event.
 type NotificationHandler func(*PgConn, *Notification)
 
+// ParameterStatusHandler is a function that can handle parameter status messages received from the PostgreSQL server.
+// The server reports the initial value of certain run-time parameters during connection establishment and reports any
+// subsequent change (e.g. SET TimeZone). The *PgConn is provided so the handler is aware of the origin of the message,
+// but it must not invoke any query method. When the handler is called the new value is already visible through
+// ParameterStatus.
+type ParameterStatusHandler func(pgConn *PgConn, name, value string)
+
 // PgConn is a low-level PostgreSQL connection handle. It is not safe for concurrent usage.
 type PgConn struct {
 	conn              net.Conn
@@ -575,6 +582,9 @@ func (pgConn *PgConn) receiveMessage() (pgproto3.BackendMessage, error) {
 		pgConn.txStatus = msg.TxStatus
 	case *pgproto3.ParameterStatus:
 		pgConn.parameterStatuses[msg.Name] = msg.Value
+		if pgConn.config.OnParameterStatus != nil {
+			pgConn.config.OnParameterStatus(pgConn, msg.Name, msg.Value)
+		}
 	case *pgproto3.ErrorResponse:
 		err := ErrorResponseToPgError(msg)
 		if pgConn.config.OnPgError != nil && !pgConn.config.OnPgError(pgConn, err) {
@@ -2277,6 +2287,199 @@ func (p *Pipeline) Close() error {
 	return p.err
 }
 
+// CopyBoth is a bidirectional copy stream started by StartCopyBoth. Both the client and the server send CopyData
+// messages until one side ends the stream. It is used by streaming replication (e.g. START_REPLICATION) and by
+// extensions that use the COPY BOTH sub-protocol.
+//
+// The connection is busy until Close is called.
+type CopyBoth struct {
+	conn *PgConn
+	ctx  context.Context
+
+	serverDone bool
+
+	err    error
+	closed bool
+}
+
+// StartCopyBoth executes sql which must start a COPY BOTH stream. ctx is in effect until Close is called. If sql does
+// not start a COPY BOTH stream, its results are discarded and an error is returned. A COPY FROM STDIN started instead
+// is aborted with CopyFail.
+func (pgConn *PgConn) StartCopyBoth(ctx context.Context, sql string) (*CopyBoth, error) {
+	if err := pgConn.lock(); err != nil {
+		return nil, err
+	}
+
+	if ctx != context.Background() {
+		select {
+		case <-ctx.Done():
+			pgConn.unlock()
+			return nil, newContextAlreadyDoneError(ctx)
+		default:
+		}
+		pgConn.contextWatcher.Watch(ctx)
+	}
+
+	cb := &CopyBoth{conn: pgConn, ctx: ctx}
+
+	pgConn.frontend.SendQuery(&pgproto3.Query{String: sql})
+	err := pgConn.flushWithPotentialWriteReadDeadlock()
+	if err != nil {
+		pgConn.asyncClose()
+		cb.release()
+		return nil, err
+	}
+
+	var pgErr error
+	startedOtherCopy := false
+	for {
+		msg, err := pgConn.receiveMessage()
+		if err != nil {
+			pgConn.asyncClose()
+			cb.release()
+			return nil, normalizeTimeoutError(ctx, err)
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.CopyBothResponse:
+			return cb, nil
+		case *pgproto3.CopyInResponse:
+			// The server waits for data. Abort the COPY so it proceeds to ReadyForQuery.
+			startedOtherCopy = true
+			pgConn.frontend.Send(&pgproto3.CopyFail{Message: "statement did not start COPY BOTH"})
+			err := pgConn.flushWithPotentialWriteReadDeadlock()
+			if err != nil {
+				pgConn.asyncClose()
+				cb.release()
+				return nil, err
+			}
+		case *pgproto3.CopyOutResponse:
+			// The CopyData messages that follow are discarded.
+			startedOtherCopy = true
+		case *pgproto3.ErrorResponse:
+			pgErr = ErrorResponseToPgError(msg)
+		case *pgproto3.ReadyForQuery:
+			cb.release()
+			if pgErr == nil || startedOtherCopy {
+				pgErr = errors.New("statement did not start COPY BOTH")
+			}
+			return nil, pgErr
+		}
+	}
+}
+
+// SendCopyData sends data to the server as a CopyData message.
+func (cb *CopyBoth) SendCopyData(data []byte) error {
+	if cb.closed {
+		return errors.New("copy both is closed")
+	}
+	if cb.serverDone {
+		return errors.New("copy both stream ended by server")
+	}
+
+	cb.conn.frontend.Send(&pgproto3.CopyData{Data: data})
+	err := cb.conn.flushWithPotentialWriteReadDeadlock()
+	if err != nil {
+		cb.conn.asyncClose()
+		cb.fail(err)
+		return err
+	}
+
+	return nil
+}
+
+// ReceiveCopyData returns the data of the next CopyData message received from the server. The returned slice is only
+// valid until the next call to ReceiveCopyData. io.EOF is returned when the server ends the stream. If the server
+// reports an error, a *PgError is returned and the stream is ended.
+func (cb *CopyBoth) ReceiveCopyData() ([]byte, error) {
+	if cb.closed {
+		return nil, errors.New("copy both is closed")
+	}
+	if cb.serverDone {
+		if cb.err != nil {
+			return nil, cb.err
+		}
+		return nil, io.EOF
+	}
+
+	for {
+		msg, err := cb.conn.receiveMessage()
+		if err != nil {
+			cb.conn.asyncClose()
+			err = normalizeTimeoutError(cb.ctx, err)
+			cb.fail(err)
+			return nil, err
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.CopyData:
+			return msg.Data, nil
+		case *pgproto3.CopyDone:
+			cb.serverDone = true
+			return nil, io.EOF
+		case *pgproto3.ErrorResponse:
+			cb.serverDone = true
+			cb.err = ErrorResponseToPgError(msg)
+			return nil, cb.err
+		}
+	}
+}
+
+// Close ends the stream and reads the remaining results of the statement. Any CopyData still sent by the server is
+// discarded. Close returns the command tag of the statement or the error reported by the server. The connection can be
+// used again once Close returns.
+func (cb *CopyBoth) Close() (CommandTag, error) {
+	if cb.closed {
+		return CommandTag{}, cb.err
+	}
+	defer cb.release()
+
+	// After an ErrorResponse the server has already left copy mode.
+	if cb.err == nil {
+		cb.conn.frontend.Send(&pgproto3.CopyDone{})
+		err := cb.conn.flushWithPotentialWriteReadDeadlock()
+		if err != nil {
+			cb.conn.asyncClose()
+			cb.err = err
+			return CommandTag{}, err
+		}
+	}
+
+	var commandTag CommandTag
+	for {
+		msg, err := cb.conn.receiveMessage()
+		if err != nil {
+			cb.conn.asyncClose()
+			cb.err = normalizeTimeoutError(cb.ctx, err)
+			return CommandTag{}, cb.err
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.CommandComplete:
+			commandTag = cb.conn.makeCommandTag(msg.CommandTag)
+		case *pgproto3.ErrorResponse:
+			cb.err = ErrorResponseToPgError(msg)
+		case *pgproto3.ReadyForQuery:
+			return commandTag, cb.err
+		}
+	}
+}
+
+// fail records a fatal error and releases the connection.
+func (cb *CopyBoth) fail(err error) {
+	cb.err = err
+	cb.release()
+}
+
+func (cb *CopyBoth) release() {
+	if cb.closed {
+		return
+	}
+	cb.closed = true
+	cb.conn.contextWatcher.Unwatch()
+	cb.conn.unlock()
+}
+
 // DeadlineContextWatcherHandler handles canceled contexts by setting a deadline on a net.Conn.
 type DeadlineContextWatcherHandler struct {
 	Conn net.Conn
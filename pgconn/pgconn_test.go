@@ -1615,6 +1615,34 @@ end$$;`)
 	ensureConnValid(t, pgConn)
 }
 
+func TestConnOnParameterStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config, err := pgconn.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+
+	parameterStatuses := map[string]string{}
+	config.OnParameterStatus = func(c *pgconn.PgConn, name, value string) {
+		parameterStatuses[name] = value
+	}
+
+	pgConn, err := pgconn.ConnectConfig(ctx, config)
+	require.NoError(t, err)
+	defer closeConn(t, pgConn)
+
+	assert.Equal(t, pgConn.ParameterStatus("server_version"), parameterStatuses["server_version"])
+
+	_, err = pgConn.Exec(ctx, "set timezone to 'America/Chicago'").ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, "America/Chicago", parameterStatuses["TimeZone"])
+	assert.Equal(t, "America/Chicago", pgConn.ParameterStatus("TimeZone"))
+
+	ensureConnValid(t, pgConn)
+}
+
 func TestConnOnNotification(t *testing.T) {
 	t.Parallel()
 
@@ -3682,3 +3710,188 @@ func TestCancelRequestContextWatcherHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestConnCopyBoth(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	steps := pgmock.AcceptUnauthenticatedConnRequestSteps()
+	steps = append(steps, pgmock.ExpectMessage(&pgproto3.Query{String: "START_REPLICATION"}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.CopyBothResponse{}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.CopyData{Data: []byte("from server")}))
+	steps = append(steps, pgmock.ExpectMessage(&pgproto3.CopyData{Data: []byte("from client")}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.CopyDone{}))
+	steps = append(steps, pgmock.ExpectMessage(&pgproto3.CopyDone{}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.CommandComplete{CommandTag: []byte("START_STREAMING")}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.ReadyForQuery{TxStatus: 'I'}))
+	steps = append(steps, pgmock.ExpectMessage(&pgproto3.Query{String: "select 1"}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.ReadyForQuery{TxStatus: 'I'}))
+	steps = append(steps, pgmock.ExpectMessage(&pgproto3.Terminate{}))
+
+	script := &pgmock.Script{Steps: steps}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	serverErrChan := make(chan error, 1)
+	go func() {
+		defer close(serverErrChan)
+
+		conn, err := ln.Accept()
+		if err != nil {
+			serverErrChan <- err
+			return
+		}
+		defer conn.Close()
+
+		err = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		if err != nil {
+			serverErrChan <- err
+			return
+		}
+
+		err = script.Run(pgproto3.NewBackend(conn, conn))
+		if err != nil {
+			serverErrChan <- err
+			return
+		}
+	}()
+
+	host, port, _ := strings.Cut(ln.Addr().String(), ":")
+	connStr := fmt.Sprintf("sslmode=disable host=%s port=%s", host, port)
+
+	ctx, cancel = context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	pgConn, err := pgconn.Connect(ctx, connStr)
+	require.NoError(t, err)
+
+	cb, err := pgConn.StartCopyBoth(ctx, "START_REPLICATION")
+	require.NoError(t, err)
+
+	data, err := cb.ReceiveCopyData()
+	require.NoError(t, err)
+	require.Equal(t, "from server", string(data))
+
+	err = cb.SendCopyData([]byte("from client"))
+	require.NoError(t, err)
+
+	_, err = cb.ReceiveCopyData()
+	require.ErrorIs(t, err, io.EOF)
+
+	commandTag, err := cb.Close()
+	require.NoError(t, err)
+	require.Equal(t, "START_STREAMING", commandTag.String())
+
+	_, err = pgConn.Exec(ctx, "select 1").ReadAll()
+	require.NoError(t, err)
+
+	err = pgConn.Close(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, <-serverErrChan)
+}
+
+func TestConnStartCopyBothNotCopyBoth(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pgConn, err := pgconn.Connect(ctx, os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	defer closeConn(t, pgConn)
+
+	cb, err := pgConn.StartCopyBoth(ctx, "select 1")
+	require.EqualError(t, err, "statement did not start COPY BOTH")
+	require.Nil(t, cb)
+
+	cb, err = pgConn.StartCopyBoth(ctx, "select 1/0")
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	require.Equal(t, "22012", pgErr.Code)
+	require.Nil(t, cb)
+
+	ensureConnValid(t, pgConn)
+}
+
+func TestConnStartCopyBothOtherCopy(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	steps := pgmock.AcceptUnauthenticatedConnRequestSteps()
+	steps = append(steps, pgmock.ExpectMessage(&pgproto3.Query{String: "copy t from stdin"}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.CopyInResponse{}))
+	steps = append(steps, pgmock.ExpectMessage(&pgproto3.CopyFail{Message: "statement did not start COPY BOTH"}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.ErrorResponse{Severity: "ERROR", Code: "57014", Message: "COPY from stdin failed"}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.ReadyForQuery{TxStatus: 'I'}))
+	steps = append(steps, pgmock.ExpectMessage(&pgproto3.Query{String: "copy t to stdout"}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.CopyOutResponse{}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.CopyData{Data: []byte("1\n")}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.CopyDone{}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.CommandComplete{CommandTag: []byte("COPY 1")}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.ReadyForQuery{TxStatus: 'I'}))
+	steps = append(steps, pgmock.ExpectMessage(&pgproto3.Query{String: "select 1"}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")}))
+	steps = append(steps, pgmock.SendMessage(&pgproto3.ReadyForQuery{TxStatus: 'I'}))
+	steps = append(steps, pgmock.ExpectMessage(&pgproto3.Terminate{}))
+
+	script := &pgmock.Script{Steps: steps}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	serverErrChan := make(chan error, 1)
+	go func() {
+		defer close(serverErrChan)
+
+		conn, err := ln.Accept()
+		if err != nil {
+			serverErrChan <- err
+			return
+		}
+		defer conn.Close()
+
+		err = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		if err != nil {
+			serverErrChan <- err
+			return
+		}
+
+		err = script.Run(pgproto3.NewBackend(conn, conn))
+		if err != nil {
+			serverErrChan <- err
+			return
+		}
+	}()
+
+	host, port, _ := strings.Cut(ln.Addr().String(), ":")
+	connStr := fmt.Sprintf("sslmode=disable host=%s port=%s", host, port)
+
+	ctx, cancel = context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	pgConn, err := pgconn.Connect(ctx, connStr)
+	require.NoError(t, err)
+
+	cb, err := pgConn.StartCopyBoth(ctx, "copy t from stdin")
+	require.EqualError(t, err, "statement did not start COPY BOTH")
+	require.Nil(t, cb)
+
+	cb, err = pgConn.StartCopyBoth(ctx, "copy t to stdout")
+	require.EqualError(t, err, "statement did not start COPY BOTH")
+	require.Nil(t, cb)
+
+	_, err = pgConn.Exec(ctx, "select 1").ReadAll()
+	require.NoError(t, err)
+
+	err = pgConn.Close(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, <-serverErrChan)
+}
@@ -501,10 +501,26 @@ func (w durationWrapper) SkipUnderlyingTypePlan() {}
 
 func (w *durationWrapper) ScanInterval(v Interval) error {
 	if !v.Valid {
-		return fmt.Errorf("cannot scan NULL into *time.Interval")
+		return fmt.Errorf("cannot scan NULL into *time.Duration")
 	}
 
-	us := int64(v.Months)*microsecondsPerMonth + int64(v.Days)*microsecondsPerDay + v.Microseconds
+	// A month does not have a fixed length.
+	if v.Months != 0 {
+		return fmt.Errorf("cannot scan interval with %d months into *time.Duration", v.Months)
+	}
+
+	// Check the range of each step so the integer math cannot overflow.
+	const maxMicroseconds = math.MaxInt64 / int64(time.Microsecond)
+	const maxDays = maxMicroseconds / microsecondsPerDay
+	if int64(v.Days) > maxDays || int64(v.Days) < -maxDays {
+		return fmt.Errorf("interval with %d days and %d microseconds is out of range for time.Duration", v.Days, v.Microseconds)
+	}
+	dayMicroseconds := int64(v.Days) * microsecondsPerDay
+	if v.Microseconds > maxMicroseconds-dayMicroseconds || v.Microseconds < -maxMicroseconds-dayMicroseconds {
+		return fmt.Errorf("interval with %d days and %d microseconds is out of range for time.Duration", v.Days, v.Microseconds)
+	}
+
+	us := dayMicroseconds + v.Microseconds
 	*w = durationWrapper(time.Duration(us) * time.Microsecond)
 	return nil
 }
@@ -2,12 +2,14 @@ package pgtype_test
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxtest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIntervalCodec(t *testing.T) {
@@ -129,7 +131,7 @@ func TestIntervalCodec(t *testing.T) {
 		},
 		{time.Hour, new(time.Duration), isExpectedEq(time.Hour)},
 		{
-			pgtype.Interval{Months: 1, Days: 1, Valid: true},
+			pgtype.Interval{Days: 31, Valid: true},
 			new(time.Duration),
 			isExpectedEq(time.Duration(2678400000000000)),
 		},
@@ -156,3 +158,38 @@ func TestIntervalTextEncode(t *testing.T) {
 		assert.Equalf(t, tt.result, string(buf), "%d", i)
 	}
 }
+
+func TestIntervalScanOutOfRangeDuration(t *testing.T) {
+	m := pgtype.NewMap()
+
+	for _, interval := range []pgtype.Interval{
+		{Days: 200000, Valid: true},
+		{Days: -200000, Valid: true},
+		{Days: math.MaxInt32, Microseconds: math.MaxInt64, Valid: true},
+		{Microseconds: math.MaxInt64, Valid: true},
+		{Microseconds: math.MinInt64, Valid: true},
+		{Days: 1, Microseconds: math.MaxInt64 / 1000, Valid: true},
+	} {
+		for _, format := range []int16{pgtype.BinaryFormatCode, pgtype.TextFormatCode} {
+			buf, err := m.Encode(pgtype.IntervalOID, format, interval, nil)
+			require.NoError(t, err)
+
+			var d time.Duration
+			err = m.Scan(pgtype.IntervalOID, format, buf, &d)
+			require.ErrorContains(t, err, "out of range for time.Duration")
+		}
+	}
+
+	for _, format := range []int16{pgtype.BinaryFormatCode, pgtype.TextFormatCode} {
+		buf, err := m.Encode(pgtype.IntervalOID, format, pgtype.Interval{Months: 1, Days: 1, Valid: true}, nil)
+		require.NoError(t, err)
+
+		var d time.Duration
+		err = m.Scan(pgtype.IntervalOID, format, buf, &d)
+		require.EqualError(t, err, "cannot scan interval with 1 months into *time.Duration")
+	}
+
+	var d time.Duration
+	err := m.Scan(pgtype.IntervalOID, pgtype.BinaryFormatCode, nil, &d)
+	require.EqualError(t, err, "cannot scan NULL into *time.Duration")
+}
@@ -1089,6 +1089,13 @@ func (m *Map) PlanScan(oid uint32, formatCode int16, target any) ScanPlan {
 	return m.planScanDepth(oid, formatCode, target, 0)
 }
 
+// CanScan reports whether a value of the PostgreSQL type oid in format formatCode can be scanned into target. Scanning
+// may still fail for individual values. e.g. NULL into a target that cannot represent NULL or an out of range number.
+func (m *Map) CanScan(oid uint32, formatCode int16, target any) bool {
+	_, failed := m.PlanScan(oid, formatCode, target).(*scanPlanFail)
+	return !failed
+}
+
 func (m *Map) planScanDepth(oid uint32, formatCode int16, target any, depth int) ScanPlan {
 	if depth > 8 {
 		return &scanPlanFail{m: m, oid: oid, formatCode: formatCode}
@@ -13,6 +13,7 @@ import (
 	"regexp"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -139,6 +140,20 @@ func TestMapScanNilIsNoOp(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestMapCanScan(t *testing.T) {
+	m := pgtype.NewMap()
+
+	var s string
+	var n int32
+	var tm time.Time
+	assert.True(t, m.CanScan(pgtype.Int4OID, pgx.BinaryFormatCode, &n))
+	assert.True(t, m.CanScan(pgtype.Int4OID, pgx.BinaryFormatCode, &s))
+	assert.True(t, m.CanScan(pgtype.TextOID, pgx.TextFormatCode, &s))
+	assert.False(t, m.CanScan(pgtype.TimestamptzOID, pgx.BinaryFormatCode, &n))
+	assert.False(t, m.CanScan(pgtype.Int4OID, pgx.BinaryFormatCode, &tm))
+	assert.False(t, m.CanScan(pgtype.Int4OID, pgx.BinaryFormatCode, nil))
+}
+
 func TestMapScanTextFormatInterfacePtr(t *testing.T) {
 	m := pgtype.NewMap()
 	var got any
@@ -0,0 +1,106 @@
+package pgxpool
+
+import (
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LongRunningAcquire describes a connection that has been acquired from a pool for a long time. This is typically
+// caused by a leak such as a Rows that is never closed or a Tx that is never committed or rolled back.
+type LongRunningAcquire struct {
+	// PID is the backend process ID of the connection.
+	PID uint32
+
+	// AcquiredAt is the time the connection was acquired.
+	AcquiredAt time.Time
+
+	// Stack is the stack trace of the goroutine that acquired the connection.
+	Stack []byte
+}
+
+// acquireTracker records the connections that are currently acquired from a pool.
+type acquireTracker struct {
+	mux      sync.Mutex
+	acquired map[*connResource]*acquireRecord
+}
+
+type acquireRecord struct {
+	acquiredAt time.Time
+	stack      []byte
+	reported   bool // reported to Config.LongRunningAcquireHandler
+}
+
+func newAcquireTracker() *acquireTracker {
+	return &acquireTracker{acquired: make(map[*connResource]*acquireRecord)}
+}
+
+func (at *acquireTracker) acquire(cr *connResource) {
+	record := &acquireRecord{acquiredAt: time.Now(), stack: debug.Stack()}
+
+	at.mux.Lock()
+	at.acquired[cr] = record
+	at.mux.Unlock()
+}
+
+func (at *acquireTracker) release(cr *connResource) {
+	at.mux.Lock()
+	delete(at.acquired, cr)
+	at.mux.Unlock()
+}
+
+// longRunning returns the connections that have been acquired for at least threshold, oldest first. If unreported is
+// true only the connections that were not returned by a previous call with unreported set are returned.
+func (at *acquireTracker) longRunning(threshold time.Duration, unreported bool) []LongRunningAcquire {
+	now := time.Now()
+
+	at.mux.Lock()
+	var lras []LongRunningAcquire
+	for cr, record := range at.acquired {
+		if now.Sub(record.acquiredAt) < threshold || (unreported && record.reported) {
+			continue
+		}
+		if unreported {
+			record.reported = true
+		}
+		lras = append(lras, LongRunningAcquire{
+			PID:        cr.conn.PgConn().PID(),
+			AcquiredAt: record.acquiredAt,
+			Stack:      record.stack,
+		})
+	}
+	at.mux.Unlock()
+
+	sort.Slice(lras, func(i, j int) bool { return lras[i].AcquiredAt.Before(lras[j].AcquiredAt) })
+
+	return lras
+}
+
+// LongRunningAcquires returns the connections that have been acquired for at least threshold, oldest first. It
+// returns nil unless Config.TrackAcquires is true or Config.LongRunningAcquireThreshold is greater than 0.
+func (p *Pool) LongRunningAcquires(threshold time.Duration) []LongRunningAcquire {
+	if p.acquireTracker == nil {
+		return nil
+	}
+	return p.acquireTracker.longRunning(threshold, false)
+}
+
+// backgroundLongRunningAcquireCheck reports each connection that has been acquired for longer than
+// p.longRunningAcquireThreshold once.
+func (p *Pool) backgroundLongRunningAcquireCheck() {
+	handler := p.config.LongRunningAcquireHandler
+
+	ticker := time.NewTicker(p.longRunningAcquireThreshold / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.closeChan:
+			return
+		case <-ticker.C:
+			for _, lra := range p.acquireTracker.longRunning(p.longRunningAcquireThreshold, true) {
+				handler(lra)
+			}
+		}
+	}
+}
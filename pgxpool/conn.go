@@ -26,6 +26,10 @@ func (c *Conn) Release() {
 	res := c.res
 	c.res = nil
 
+	if c.p.acquireTracker != nil {
+		c.p.acquireTracker.release(res.Value())
+	}
+
 	if c.p.releaseTracer != nil {
 		c.p.releaseTracer.TraceRelease(c.p, TraceReleaseData{Conn: conn})
 	}
@@ -51,13 +55,13 @@ func (c *Conn) Release() {
 	}
 
 	if c.p.afterRelease == nil {
-		res.Release()
+		c.p.releaseResource(res)
 		return
 	}
 
 	go func() {
 		if c.p.afterRelease(conn) {
-			res.Release()
+			c.p.releaseResource(res)
 		} else {
 			res.Destroy()
 			// Signal to the health check to run since we just destroyed a connections
@@ -78,6 +82,9 @@ func (c *Conn) Hijack() *pgx.Conn {
 	res := c.res
 	c.res = nil
 
+	if c.p.acquireTracker != nil {
+		c.p.acquireTracker.release(res.Value())
+	}
 	res.Hijack()
 
 	return conn
@@ -121,6 +128,34 @@ func (c *Conn) Conn() *pgx.Conn {
 	return c.connResource().conn
 }
 
+// SetTag tags the underlying connection with tag. The tag stays with the connection when it is released so it can be
+// acquired again with Pool.AcquireTagged.
+func (c *Conn) SetTag(tag string) {
+	cr := c.connResource()
+	if _, ok := cr.tags[tag]; ok {
+		return
+	}
+	if cr.tags == nil {
+		cr.tags = make(map[string]struct{})
+	}
+	cr.tags[tag] = struct{}{}
+}
+
+// RemoveTag removes tag from the underlying connection.
+func (c *Conn) RemoveTag(tag string) {
+	cr := c.connResource()
+	if _, ok := cr.tags[tag]; !ok {
+		return
+	}
+	delete(cr.tags, tag)
+}
+
+// HasTag returns true if the underlying connection is tagged with tag.
+func (c *Conn) HasTag(tag string) bool {
+	_, ok := c.connResource().tags[tag]
+	return ok
+}
+
 func (c *Conn) connResource() *connResource {
 	return c.res.Value()
 }
@@ -0,0 +1,280 @@
+package pgxpool
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// EndpointStat is the health of one host of a multi-host connection config. See Config.EndpointQuarantine.
+type EndpointStat struct {
+	Host string
+	Port uint16
+
+	// ConsecutiveFailures is the number of connection attempts to the host that failed since the last successful one.
+	ConsecutiveFailures int
+
+	// QuarantinedUntil is the time until which the host is only tried after all healthy hosts. It is the zero time if
+	// the host has never failed.
+	QuarantinedUntil time.Time
+
+	// LastError is the error of the last failed connection attempt to the host. It is nil if the last attempt succeeded.
+	LastError error
+}
+
+// Quarantined returns true if the host is quarantined at time t.
+func (es *EndpointStat) Quarantined(t time.Time) bool {
+	return t.Before(es.QuarantinedUntil)
+}
+
+type endpointKey struct {
+	host string
+	port uint16
+}
+
+type endpointHealth struct {
+	failures         int
+	quarantinedUntil time.Time
+	lastErr          error
+}
+
+// endpointTracker establishes connections to the hosts of a multi-host connection config in order of their health.
+type endpointTracker struct {
+	quarantine    time.Duration
+	maxQuarantine time.Duration
+
+	mux       sync.Mutex
+	endpoints map[endpointKey]*endpointHealth
+}
+
+func newEndpointTracker(quarantine, maxQuarantine time.Duration) *endpointTracker {
+	return &endpointTracker{
+		quarantine:    quarantine,
+		maxQuarantine: maxQuarantine,
+		endpoints:     make(map[endpointKey]*endpointHealth),
+	}
+}
+
+// endpointGroup is all the fallback configs of connConfig for one host and port. e.g. with sslmode=prefer there is
+// one with TLS and one without TLS.
+type endpointGroup struct {
+	key     endpointKey
+	configs []*pgconn.FallbackConfig
+}
+
+func groupEndpoints(connConfig *pgx.ConnConfig) []*endpointGroup {
+	fallbackConfigs := append([]*pgconn.FallbackConfig{{
+		Host:      connConfig.Host,
+		Port:      connConfig.Port,
+		TLSConfig: connConfig.TLSConfig,
+	}}, connConfig.Fallbacks...)
+
+	var groups []*endpointGroup
+	groupsByKey := make(map[endpointKey]*endpointGroup)
+	for _, fc := range fallbackConfigs {
+		key := endpointKey{host: fc.Host, port: fc.Port}
+		g, ok := groupsByKey[key]
+		if !ok {
+			g = &endpointGroup{key: key}
+			groupsByKey[key] = g
+			groups = append(groups, g)
+		}
+		g.configs = append(g.configs, fc)
+	}
+
+	return groups
+}
+
+// connect establishes a connection with connConfig. Hosts that are not quarantined are tried first in the order they
+// are configured. Quarantined hosts are tried next in the order their quarantine ends. As with pgconn.ConnectConfig, a
+// host whose ValidateConnect returns a *pgconn.NotPreferredError is only used if no other host is successful.
+func (et *endpointTracker) connect(ctx context.Context, connConfig *pgx.ConnConfig) (*pgx.Conn, error) {
+	groups := groupEndpoints(connConfig)
+	if len(groups) < 2 {
+		conn, err := pgx.ConnectConfig(ctx, connConfig)
+		if len(groups) == 1 && ctx.Err() == nil {
+			et.record(groups[0].key, err)
+		}
+		return conn, err
+	}
+
+	now := time.Now()
+	et.mux.Lock()
+	availableAt := make(map[endpointKey]time.Time, len(groups))
+	for _, g := range groups {
+		if eh, ok := et.endpoints[g.key]; ok && now.Before(eh.quarantinedUntil) {
+			availableAt[g.key] = eh.quarantinedUntil
+		}
+	}
+	et.mux.Unlock()
+	sort.SliceStable(groups, func(i, j int) bool {
+		return availableAt[groups[i].key].Before(availableAt[groups[j].key])
+	})
+
+	var notPreferred *endpointGroup
+	var errs []error
+	for _, g := range groups {
+		conn, err := connectEndpoint(ctx, connConfig, g, false)
+		if err == nil {
+			et.record(g.key, nil)
+			return conn, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		var npErr *endpointNotPreferredError
+		if errors.As(err, &npErr) {
+			if notPreferred == nil {
+				notPreferred = g
+			}
+			continue
+		}
+
+		errs = append(errs, err)
+		if isFatalConnectError(err) {
+			// The server is reachable. The error is the same for every host.
+			return nil, errors.Join(errs...)
+		}
+		et.record(g.key, err)
+	}
+
+	if notPreferred != nil {
+		conn, err := connectEndpoint(ctx, connConfig, notPreferred, true)
+		if err == nil {
+			et.record(notPreferred.key, nil)
+			return conn, nil
+		}
+		if ctx.Err() == nil {
+			et.record(notPreferred.key, err)
+		}
+		errs = append(errs, err)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// connectEndpoint establishes a connection to the host of g. If acceptNotPreferred is false a *pgconn.NotPreferredError
+// from ValidateConnect is returned as an *endpointNotPreferredError. Otherwise it is ignored.
+func connectEndpoint(ctx context.Context, connConfig *pgx.ConnConfig, g *endpointGroup, acceptNotPreferred bool) (*pgx.Conn, error) {
+	cc := connConfig.Copy()
+	cc.Host = g.configs[0].Host
+	cc.Port = g.configs[0].Port
+	cc.TLSConfig = g.configs[0].TLSConfig
+	cc.Fallbacks = g.configs[1:]
+
+	if validateConnect := cc.ValidateConnect; validateConnect != nil {
+		cc.ValidateConnect = func(ctx context.Context, pgConn *pgconn.PgConn) error {
+			err := validateConnect(ctx, pgConn)
+			var npErr *pgconn.NotPreferredError
+			if errors.As(err, &npErr) {
+				if acceptNotPreferred {
+					return nil
+				}
+				return &endpointNotPreferredError{err: err}
+			}
+			return err
+		}
+	}
+
+	return pgx.ConnectConfig(ctx, cc)
+}
+
+// endpointNotPreferredError deliberately does not unwrap to the *pgconn.NotPreferredError. Otherwise pgconn would
+// immediately connect to the host again and use it.
+type endpointNotPreferredError struct {
+	err error
+}
+
+func (e *endpointNotPreferredError) Error() string {
+	return e.err.Error()
+}
+
+// isFatalConnectError returns true if err is an error that pgconn.ConnectConfig does not try other hosts for.
+func isFatalConnectError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	switch pgErr.Code {
+	case "28P01", // invalid_password
+		"3D000", // invalid_catalog_name
+		"42501": // insufficient_privilege
+		return true
+	}
+	return false
+}
+
+// record records the result of a connection attempt to the host of key.
+func (et *endpointTracker) record(key endpointKey, err error) {
+	et.mux.Lock()
+	defer et.mux.Unlock()
+
+	eh, ok := et.endpoints[key]
+	if !ok {
+		if err == nil {
+			return
+		}
+		eh = &endpointHealth{}
+		et.endpoints[key] = eh
+	}
+
+	if err == nil {
+		eh.failures = 0
+		eh.quarantinedUntil = time.Time{}
+		eh.lastErr = nil
+		return
+	}
+
+	eh.failures++
+	eh.lastErr = err
+
+	quarantine := et.quarantine
+	for i := 1; i < eh.failures && quarantine < et.maxQuarantine; i++ {
+		quarantine *= 2
+	}
+	if et.maxQuarantine > et.quarantine && quarantine > et.maxQuarantine {
+		quarantine = et.maxQuarantine
+	}
+	eh.quarantinedUntil = time.Now().Add(quarantine)
+}
+
+func (et *endpointTracker) stats() []EndpointStat {
+	et.mux.Lock()
+	defer et.mux.Unlock()
+
+	stats := make([]EndpointStat, 0, len(et.endpoints))
+	for key, eh := range et.endpoints {
+		stats = append(stats, EndpointStat{
+			Host:                key.host,
+			Port:                key.port,
+			ConsecutiveFailures: eh.failures,
+			QuarantinedUntil:    eh.quarantinedUntil,
+			LastError:           eh.lastErr,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Host != stats[j].Host {
+			return stats[i].Host < stats[j].Host
+		}
+		return stats[i].Port < stats[j].Port
+	})
+
+	return stats
+}
+
+// EndpointStats returns the health of each host that a connection attempt failed for since the pool was created. It
+// returns nil if Config.EndpointQuarantine is 0.
+func (p *Pool) EndpointStats() []EndpointStat {
+	if p.endpoints == nil {
+		return nil
+	}
+	return p.endpoints.stats()
+}
@@ -0,0 +1,165 @@
+package pgxpool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var defaultListenerReconnectDelay = time.Second
+
+// NotificationHandler handles a notification received by a Listener. conn is the connection the notification was
+// received on. It may be used for queries but it must not be used after the handler returns.
+type NotificationHandler func(ctx context.Context, notification *pgconn.Notification, conn *pgx.Conn) error
+
+// Listener dispatches notifications sent with NOTIFY to handlers. LISTEN only applies to the connection that runs it so
+// Listener dedicates a connection acquired from Pool to listening for the channels of all handlers. If the connection
+// is lost, Listener acquires a new connection and listens for the channels again. Notifications sent while there is no
+// connection are lost.
+type Listener struct {
+	// Pool is the pool the connection is acquired from. It must be set before Listen is called.
+	Pool *Pool
+
+	// ReconnectDelay is the duration Listen waits after the connection failed before acquiring a new connection. The
+	// default is 1 second.
+	ReconnectDelay time.Duration
+
+	// LogError is called with errors that Listen recovers from, including errors returned by handlers. It may be nil.
+	LogError func(context.Context, error)
+
+	mux       sync.Mutex
+	handlers  map[string]NotificationHandler
+	interrupt context.CancelFunc // interrupts waiting for a notification so changed handlers are applied
+}
+
+// Handle sets the handler for notifications on channel. If handler is nil the handler for channel is removed. Handle
+// may be called while Listen is running.
+func (l *Listener) Handle(channel string, handler NotificationHandler) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if handler == nil {
+		delete(l.handlers, channel)
+	} else {
+		if l.handlers == nil {
+			l.handlers = make(map[string]NotificationHandler)
+		}
+		l.handlers[channel] = handler
+	}
+
+	if l.interrupt != nil {
+		l.interrupt()
+	}
+}
+
+// Listen listens for notifications until ctx is done. Handlers are called sequentially on the goroutine that called
+// Listen so a slow handler delays other notifications. Listen always returns a non-nil error. Only one Listen may run
+// at a time.
+func (l *Listener) Listen(ctx context.Context) error {
+	reconnectDelay := l.ReconnectDelay
+	if reconnectDelay == 0 {
+		reconnectDelay = defaultListenerReconnectDelay
+	}
+
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		l.logError(ctx, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	c, err := l.Pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		l.mux.Lock()
+		l.interrupt = nil
+		l.mux.Unlock()
+
+		// Do not return a connection that is still listening to the pool.
+		if !c.Conn().IsClosed() {
+			unlistenCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 15*time.Second)
+			_, err := c.Exec(unlistenCtx, "unlisten *")
+			if err != nil {
+				c.Conn().Close(unlistenCtx)
+			}
+			cancel()
+		}
+		c.Release()
+	}()
+
+	listening := make(map[string]struct{})
+	for {
+		l.mux.Lock()
+		var listen, unlisten []string
+		for channel := range l.handlers {
+			if _, ok := listening[channel]; !ok {
+				listen = append(listen, channel)
+			}
+		}
+		for channel := range listening {
+			if _, ok := l.handlers[channel]; !ok {
+				unlisten = append(unlisten, channel)
+			}
+		}
+		waitCtx, cancel := context.WithCancel(ctx)
+		l.interrupt = cancel
+		l.mux.Unlock()
+
+		for _, channel := range listen {
+			_, err := c.Exec(ctx, "listen "+pgx.Identifier{channel}.Sanitize())
+			if err != nil {
+				cancel()
+				return err
+			}
+			listening[channel] = struct{}{}
+		}
+		for _, channel := range unlisten {
+			_, err := c.Exec(ctx, "unlisten "+pgx.Identifier{channel}.Sanitize())
+			if err != nil {
+				cancel()
+				return err
+			}
+			delete(listening, channel)
+		}
+
+		notification, err := c.Conn().WaitForNotification(waitCtx)
+		interrupted := waitCtx.Err() != nil
+		cancel()
+		if err != nil {
+			if interrupted && ctx.Err() == nil {
+				continue
+			}
+			return err
+		}
+
+		l.mux.Lock()
+		handler := l.handlers[notification.Channel]
+		l.mux.Unlock()
+		if handler != nil {
+			err := handler(ctx, notification, c.Conn())
+			if err != nil {
+				l.logError(ctx, err)
+			}
+		}
+	}
+}
+
+func (l *Listener) logError(ctx context.Context, err error) {
+	if l.LogError != nil {
+		l.LogError(ctx, err)
+	}
+}
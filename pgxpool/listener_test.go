@@ -0,0 +1,80 @@
+package pgxpool_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/pgxtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListener(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	defer pool.Close()
+
+	c, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	pgxtest.SkipCockroachDB(t, c.Conn(), "Server does not support LISTEN / NOTIFY (https://github.com/cockroachdb/cockroach/issues/41522)")
+	c.Release()
+
+	type received struct {
+		notification *pgconn.Notification
+		pid          uint32
+	}
+	receivedChan := make(chan received, 100)
+	handler := func(ctx context.Context, notification *pgconn.Notification, conn *pgx.Conn) error {
+		receivedChan <- received{notification: notification, pid: conn.PgConn().PID()}
+		return nil
+	}
+
+	listener := &pgxpool.Listener{Pool: pool, ReconnectDelay: 10 * time.Millisecond}
+	listener.Handle("pgxpool_listener_a", handler)
+
+	listenCtx, cancelListen := context.WithCancel(ctx)
+	listenErrChan := make(chan error, 1)
+	go func() { listenErrChan <- listener.Listen(listenCtx) }()
+
+	// A notification sent before the listener runs LISTEN is lost so keep notifying until one is received.
+	waitForNotification := func(channel, payload string) received {
+		for {
+			_, err := pool.Exec(ctx, "select pg_notify($1, $2)", channel, payload)
+			require.NoError(t, err)
+
+			select {
+			case r := <-receivedChan:
+				if r.notification.Channel == channel && r.notification.Payload == payload {
+					return r
+				}
+			case <-time.After(50 * time.Millisecond):
+			case <-ctx.Done():
+				t.Fatal("notification was not received")
+			}
+		}
+	}
+
+	r := waitForNotification("pgxpool_listener_a", "hello")
+
+	// Handlers can be added while listening.
+	listener.Handle("pgxpool_listener_b", handler)
+	waitForNotification("pgxpool_listener_b", "world")
+
+	// The listener reconnects after the connection is lost.
+	_, err = pool.Exec(ctx, "select pg_terminate_backend($1)", r.pid)
+	require.NoError(t, err)
+	r2 := waitForNotification("pgxpool_listener_a", "again")
+	require.NotEqual(t, r.pid, r2.pid)
+
+	cancelListen()
+	require.ErrorIs(t, <-listenErrChan, context.Canceled)
+}
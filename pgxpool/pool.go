@@ -2,6 +2,7 @@ package pgxpool
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"runtime"
@@ -20,13 +21,38 @@ var defaultMinConns = int32(0)
 var defaultMaxConnLifetime = time.Hour
 var defaultMaxConnIdleTime = time.Minute * 30
 var defaultHealthCheckPeriod = time.Minute
+var defaultAcquireValidationIdleTime = time.Second
+
+// AcquireValidation is the check that Acquire makes that a connection is still usable before returning it. See
+// Config.AcquireValidation.
+type AcquireValidation int
+
+const (
+	// AcquireValidationPing pings the server. This round trip detects every broken connection.
+	AcquireValidationPing AcquireValidation = iota
+
+	// AcquireValidationCheckConn checks the connection with a non-blocking read. This avoids the latency of a round
+	// trip but it cannot detect all types of broken connections. See pgconn.PgConn.CheckConn.
+	AcquireValidationCheckConn
+
+	// AcquireValidationNone does not check the connection.
+	AcquireValidationNone
+)
+
+// ErrPoolExhausted is returned (possibly wrapped) by Acquire when no connection became available within
+// Config.AcquireTimeout or when Config.MaxAcquireWaiters goroutines are already waiting for a connection.
+var ErrPoolExhausted = errors.New("pool exhausted")
 
 type connResource struct {
-	conn       *pgx.Conn
-	conns      []Conn
-	poolRows   []poolRow
-	poolRowss  []poolRows
-	maxAgeTime time.Time
+	conn             *pgx.Conn
+	conns            []Conn
+	poolRows         []poolRow
+	poolRowss        []poolRows
+	maxAgeTime       time.Time
+	preparedStmtsGen int64               // value of Pool.preparedStmtsGen when Config.PreparedStatements were prepared
+	resetCount       int64               // value of Pool.resetCount when the connection was established
+	tags             map[string]struct{} // only accessed by the holder of the resource
+	idleTaggedTime   time.Time           // when the connection was last put in Pool.idleTagged; zero if it was not
 }
 
 func (cr *connResource) getConn(p *Pool, res *puddle.Resource[*connResource]) *Conn {
@@ -40,6 +66,10 @@ func (cr *connResource) getConn(p *Pool, res *puddle.Resource[*connResource]) *C
 	c.res = res
 	c.p = p
 
+	if p.acquireTracker != nil {
+		p.acquireTracker.acquire(cr)
+	}
+
 	return c
 }
 
@@ -78,6 +108,8 @@ type Pool struct {
 	newConnsCount        int64
 	lifetimeDestroyCount int64
 	idleDestroyCount     int64
+	preparedStmtsGen     int64 // incremented by InvalidatePreparedStatements
+	resetCount           int64 // incremented by Reset
 
 	p                     *puddle.Pool[*connResource]
 	config                *Config
@@ -92,9 +124,29 @@ type Pool struct {
 	maxConnLifetimeJitter time.Duration
 	maxConnIdleTime       time.Duration
 	healthCheckPeriod     time.Duration
+	acquireTimeout        time.Duration
+	maxAcquireWaiters     int32
+	acquireValidation     AcquireValidation
+	acquireValidationIdle time.Duration
+
+	acquireWaiters int32 // accessed with atomics
+
+	endpoints *endpointTracker // nil if Config.EndpointQuarantine is 0
+
+	// Idle tagged connections are kept in idleTagged instead of with the idle connections of p so AcquireTagged can find
+	// them by tag. They remain acquired from p while they are there. No connection is added while acquiringCount is
+	// non-zero as an acquire waiting for p could not get it.
+	idleTaggedMux  sync.Mutex
+	idleTagged     []*puddle.Resource[*connResource]
+	acquiringCount int
+
+	acquireTracker              *acquireTracker // nil unless acquires are tracked
+	longRunningAcquireThreshold time.Duration
 
 	healthCheckChan chan struct{}
 
+	initialIdleDoneChan chan struct{} // closed when the connections initially created for MinConns are established
+
 	acquireTracer AcquireTracer
 	releaseTracer ReleaseTracer
 
@@ -147,6 +199,60 @@ type Config struct {
 	// HealthCheckPeriod is the duration between checks of the health of idle connections.
 	HealthCheckPeriod time.Duration
 
+	// PreparedStatements maps names to SQL of statements that are prepared on every connection after AfterConnect. This
+	// ensures that frequently used statements are always prepared on the server no matter which connection runs them.
+	// See Pool.InvalidatePreparedStatements.
+	PreparedStatements map[string]string
+
+	// AcquireTimeout is the maximum duration Acquire waits for a connection, including the time to establish a new
+	// connection. If it is exceeded, Acquire returns an error wrapping ErrPoolExhausted. The context passed to Acquire
+	// may still end the wait earlier. If it is 0 there is no limit.
+	AcquireTimeout time.Duration
+
+	// MaxAcquireWaiters is the maximum number of goroutines that can wait in Acquire for a connection when all
+	// connections are in use. Further calls to Acquire fail immediately with ErrPoolExhausted. This sheds load under
+	// overload instead of queueing requests indefinitely. If it is 0 there is no limit.
+	MaxAcquireWaiters int32
+
+	// AcquireValidation is the check Acquire makes that a connection that was idle for at least
+	// AcquireValidationIdleTime is still usable. If the check fails the connection is destroyed and a different
+	// connection is acquired. The default is AcquireValidationPing.
+	AcquireValidation AcquireValidation
+
+	// AcquireValidationIdleTime is the duration a connection must have been idle for Acquire to check it with
+	// AcquireValidation. If it is 0 every acquired connection is checked. The default is 1 second.
+	AcquireValidationIdleTime time.Duration
+
+	// TrackAcquires records the time and the stack trace of every Acquire until the connection is released. This makes
+	// it possible to find leaked connections with Pool.LongRunningAcquires. Recording the stack trace adds overhead to
+	// every Acquire.
+	TrackAcquires bool
+
+	// LongRunningAcquireThreshold enables TrackAcquires and calls LongRunningAcquireHandler once for every connection
+	// that is not released within LongRunningAcquireThreshold of being acquired. If it is 0 connections are not checked.
+	LongRunningAcquireThreshold time.Duration
+
+	// LongRunningAcquireHandler is called in a background goroutine for connections found by
+	// LongRunningAcquireThreshold. It is required when LongRunningAcquireThreshold is greater than 0.
+	LongRunningAcquireHandler func(LongRunningAcquire)
+
+	// PingOnCreate causes New and NewWithConfig to establish a connection and ping the server before returning. An error
+	// such as an unreachable host or invalid credentials is then returned immediately instead of by the first query. By
+	// default the pool is created without connecting.
+	PingOnCreate bool
+
+	// EndpointQuarantine enables tracking the health of each host of a multi-host ConnConfig (e.g. host=pg1,pg2,pg3).
+	// When a connection attempt to a host fails, including when ValidateConnect rejects the host after a failover, the
+	// host is quarantined for EndpointQuarantine. New connections try hosts that are not quarantined first and only try
+	// quarantined hosts after all others failed. This avoids waiting for a dead primary for every new connection. If it
+	// is 0 the hosts are tried in the configured order for every new connection. See Pool.EndpointStats.
+	EndpointQuarantine time.Duration
+
+	// MaxEndpointQuarantine is the maximum duration of a quarantine. The quarantine of a host doubles with each
+	// consecutive failure until it reaches MaxEndpointQuarantine. If MaxEndpointQuarantine is not greater than
+	// EndpointQuarantine the quarantine does not grow.
+	MaxEndpointQuarantine time.Duration
+
 	createdByParseConfig bool // Used to enforce created by ParseConfig rule.
 }
 
@@ -181,6 +287,10 @@ func NewWithConfig(ctx context.Context, config *Config) (*Pool, error) {
 		panic("config must be created by ParseConfig")
 	}
 
+	if config.LongRunningAcquireThreshold > 0 && config.LongRunningAcquireHandler == nil {
+		return nil, errors.New("LongRunningAcquireHandler is required when LongRunningAcquireThreshold is set")
+	}
+
 	p := &Pool{
 		config:                config,
 		beforeConnect:         config.BeforeConnect,
@@ -194,10 +304,24 @@ func NewWithConfig(ctx context.Context, config *Config) (*Pool, error) {
 		maxConnLifetimeJitter: config.MaxConnLifetimeJitter,
 		maxConnIdleTime:       config.MaxConnIdleTime,
 		healthCheckPeriod:     config.HealthCheckPeriod,
+		acquireTimeout:        config.AcquireTimeout,
+		maxAcquireWaiters:     config.MaxAcquireWaiters,
+		acquireValidation:     config.AcquireValidation,
+		acquireValidationIdle: config.AcquireValidationIdleTime,
 		healthCheckChan:       make(chan struct{}, 1),
+		initialIdleDoneChan:   make(chan struct{}),
 		closeChan:             make(chan struct{}),
 	}
 
+	if config.EndpointQuarantine > 0 {
+		p.endpoints = newEndpointTracker(config.EndpointQuarantine, config.MaxEndpointQuarantine)
+	}
+
+	if config.TrackAcquires || config.LongRunningAcquireThreshold > 0 {
+		p.acquireTracker = newAcquireTracker()
+		p.longRunningAcquireThreshold = config.LongRunningAcquireThreshold
+	}
+
 	if t, ok := config.ConnConfig.Tracer.(AcquireTracer); ok {
 		p.acquireTracer = t
 	}
@@ -211,6 +335,7 @@ func NewWithConfig(ctx context.Context, config *Config) (*Pool, error) {
 		&puddle.Config[*connResource]{
 			Constructor: func(ctx context.Context) (*connResource, error) {
 				atomic.AddInt64(&p.newConnsCount, 1)
+				resetCount := atomic.LoadInt64(&p.resetCount)
 				connConfig := p.config.ConnConfig.Copy()
 
 				// Connection will continue in background even if Acquire is canceled. Ensure that a connect won't hang forever.
@@ -224,7 +349,13 @@ func NewWithConfig(ctx context.Context, config *Config) (*Pool, error) {
 					}
 				}
 
-				conn, err := pgx.ConnectConfig(ctx, connConfig)
+				var conn *pgx.Conn
+				var err error
+				if p.endpoints != nil {
+					conn, err = p.endpoints.connect(ctx, connConfig)
+				} else {
+					conn, err = pgx.ConnectConfig(ctx, connConfig)
+				}
 				if err != nil {
 					return nil, err
 				}
@@ -237,6 +368,13 @@ func NewWithConfig(ctx context.Context, config *Config) (*Pool, error) {
 					}
 				}
 
+				preparedStmtsGen := atomic.LoadInt64(&p.preparedStmtsGen)
+				err = p.prepareStatements(ctx, conn)
+				if err != nil {
+					conn.Close(ctx)
+					return nil, err
+				}
+
 				jitterSecs := rand.Float64() * config.MaxConnLifetimeJitter.Seconds()
 				maxAgeTime := time.Now().Add(config.MaxConnLifetime).Add(time.Duration(jitterSecs) * time.Second)
 
@@ -246,6 +384,9 @@ func NewWithConfig(ctx context.Context, config *Config) (*Pool, error) {
 					poolRows:   make([]poolRow, 64),
 					poolRowss:  make([]poolRows, 64),
 					maxAgeTime: maxAgeTime,
+
+					preparedStmtsGen: preparedStmtsGen,
+					resetCount:       resetCount,
 				}
 
 				return cr, nil
@@ -272,9 +413,22 @@ func NewWithConfig(ctx context.Context, config *Config) (*Pool, error) {
 
 	go func() {
 		p.createIdleResources(ctx, int(p.minConns))
+		close(p.initialIdleDoneChan)
 		p.backgroundHealthCheck()
 	}()
 
+	if p.longRunningAcquireThreshold > 0 {
+		go p.backgroundLongRunningAcquireCheck()
+	}
+
+	if config.PingOnCreate {
+		err = p.Ping(ctx)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+	}
+
 	return p, nil
 }
 
@@ -287,6 +441,12 @@ func NewWithConfig(ctx context.Context, config *Config) (*Pool, error) {
 //   - pool_max_conn_idle_time: duration string (default 30 minutes)
 //   - pool_health_check_period: duration string (default 1 minute)
 //   - pool_max_conn_lifetime_jitter: duration string (default 0)
+//   - pool_acquire_timeout: duration string (default 0)
+//   - pool_max_acquire_waiters: integer 0 or greater (default 0)
+//   - pool_acquire_validation: ping, check_conn, or none (default ping)
+//   - pool_acquire_validation_idle_time: duration string (default 1 second)
+//   - pool_endpoint_quarantine: duration string (default 0)
+//   - pool_max_endpoint_quarantine: duration string (default 0)
 //
 // See Config for definitions of these arguments.
 //
@@ -376,6 +536,67 @@ func ParseConfig(connString string) (*Config, error) {
 		config.MaxConnLifetimeJitter = d
 	}
 
+	if s, ok := config.ConnConfig.Config.RuntimeParams["pool_acquire_timeout"]; ok {
+		delete(connConfig.Config.RuntimeParams, "pool_acquire_timeout")
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pool_acquire_timeout: %w", err)
+		}
+		config.AcquireTimeout = d
+	}
+
+	if s, ok := config.ConnConfig.Config.RuntimeParams["pool_max_acquire_waiters"]; ok {
+		delete(connConfig.Config.RuntimeParams, "pool_max_acquire_waiters")
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse pool_max_acquire_waiters: %w", err)
+		}
+		config.MaxAcquireWaiters = int32(n)
+	}
+
+	if s, ok := config.ConnConfig.Config.RuntimeParams["pool_acquire_validation"]; ok {
+		delete(connConfig.Config.RuntimeParams, "pool_acquire_validation")
+		switch s {
+		case "ping":
+			config.AcquireValidation = AcquireValidationPing
+		case "check_conn":
+			config.AcquireValidation = AcquireValidationCheckConn
+		case "none":
+			config.AcquireValidation = AcquireValidationNone
+		default:
+			return nil, fmt.Errorf("invalid pool_acquire_validation: %s", s)
+		}
+	}
+
+	if s, ok := config.ConnConfig.Config.RuntimeParams["pool_acquire_validation_idle_time"]; ok {
+		delete(connConfig.Config.RuntimeParams, "pool_acquire_validation_idle_time")
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pool_acquire_validation_idle_time: %w", err)
+		}
+		config.AcquireValidationIdleTime = d
+	} else {
+		config.AcquireValidationIdleTime = defaultAcquireValidationIdleTime
+	}
+
+	if s, ok := config.ConnConfig.Config.RuntimeParams["pool_endpoint_quarantine"]; ok {
+		delete(connConfig.Config.RuntimeParams, "pool_endpoint_quarantine")
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pool_endpoint_quarantine: %w", err)
+		}
+		config.EndpointQuarantine = d
+	}
+
+	if s, ok := config.ConnConfig.Config.RuntimeParams["pool_max_endpoint_quarantine"]; ok {
+		delete(connConfig.Config.RuntimeParams, "pool_max_endpoint_quarantine")
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pool_max_endpoint_quarantine: %w", err)
+		}
+		config.MaxEndpointQuarantine = d
+	}
+
 	return config, nil
 }
 
@@ -384,10 +605,41 @@ func ParseConfig(connString string) (*Config, error) {
 func (p *Pool) Close() {
 	p.closeOnce.Do(func() {
 		close(p.closeChan)
+		for _, res := range p.takeAllIdleTagged() {
+			res.Release()
+		}
 		p.p.Close()
 	})
 }
 
+// Shutdown is like Close but stops waiting for connections to be returned to the pool when ctx is done. Future Acquire
+// calls are rejected immediately. Idle connections are closed and connections in use are closed when they are
+// returned. If ctx is done first its error is returned and the remaining connections are still closed in the
+// background as they are returned.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	closedChan := make(chan struct{})
+	go func() {
+		p.Close()
+		close(closedChan)
+	}()
+
+	select {
+	case <-closedChan:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Drain closes all idle connections. Connections in use are not affected. The pool remains usable and establishes new
+// connections as needed. This can be used to move the connections to a different server, e.g. before a planned
+// restart.
+func (p *Pool) Drain() {
+	for _, res := range append(p.p.AcquireAllIdle(), p.takeAllIdleTagged()...) {
+		res.Destroy()
+	}
+}
+
 func (p *Pool) isExpired(res *puddle.Resource[*connResource]) bool {
 	return time.Now().After(res.Value().maxAgeTime)
 }
@@ -446,7 +698,7 @@ func (p *Pool) checkHealth() {
 func (p *Pool) checkConnsHealth() bool {
 	var destroyed bool
 	totalConns := p.Stat().TotalConns()
-	resources := p.p.AcquireAllIdle()
+	resources := append(p.p.AcquireAllIdle(), p.takeAllIdleTagged()...)
 	for _, res := range resources {
 		// We're okay going under minConns if the lifetime is up
 		if p.isExpired(res) && totalConns >= p.minConns {
@@ -455,14 +707,14 @@ func (p *Pool) checkConnsHealth() bool {
 			destroyed = true
 			// Since Destroy is async we manually decrement totalConns.
 			totalConns--
-		} else if res.IdleDuration() > p.maxConnIdleTime && totalConns > p.minConns {
+		} else if idleDuration(res) > p.maxConnIdleTime && totalConns > p.minConns {
 			atomic.AddInt64(&p.idleDestroyCount, 1)
 			res.Destroy()
 			destroyed = true
 			// Since Destroy is async we manually decrement totalConns.
 			totalConns--
 		} else {
-			res.ReleaseUnused()
+			p.releaseUnused(res)
 		}
 	}
 	return destroyed
@@ -508,8 +760,38 @@ func (p *Pool) createIdleResources(parentCtx context.Context, targetResources in
 	return firstError
 }
 
+// Warmup establishes connections until the pool has at least MinConns connections. Unlike the connections created in
+// the background when the pool is created, Warmup waits for the connections to be established. This allows the first
+// queries to avoid paying the cost of connecting. The first error establishing a connection is returned.
+func (p *Pool) Warmup(ctx context.Context) error {
+	select {
+	case <-p.initialIdleDoneChan:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	toCreate := p.minConns - p.Stat().TotalConns()
+	if toCreate > 0 {
+		return p.createIdleResources(ctx, int(toCreate))
+	}
+	return nil
+}
+
 // Acquire returns a connection (*Conn) from the Pool
 func (p *Pool) Acquire(ctx context.Context) (c *Conn, err error) {
+	return p.acquire(ctx, "")
+}
+
+// AcquireTagged returns an idle connection that was tagged with tag by Conn.SetTag. If there is no such connection it
+// acquires a connection like Acquire. Use Conn.HasTag to check which one was returned. This allows workflows that
+// depend on session state, such as temporary tables or settings made with set_config, to reuse the connection that
+// has that state. Tags are kept until they are removed or the connection is closed. A tagged connection that is
+// released while other connections are being acquired is not kept for AcquireTagged but is still available to Acquire.
+func (p *Pool) AcquireTagged(ctx context.Context, tag string) (c *Conn, err error) {
+	return p.acquire(ctx, tag)
+}
+
+func (p *Pool) acquire(ctx context.Context, tag string) (c *Conn, err error) {
 	if p.acquireTracer != nil {
 		ctx = p.acquireTracer.TraceAcquireStart(ctx, p, TraceAcquireStartData{})
 		defer func() {
@@ -521,28 +803,217 @@ func (p *Pool) Acquire(ctx context.Context) (c *Conn, err error) {
 		}()
 	}
 
+	if p.maxAcquireWaiters > 0 {
+		stat := p.Stat()
+		if stat.IdleConns() == 0 && stat.TotalConns() >= p.maxConns {
+			if atomic.AddInt32(&p.acquireWaiters, 1) > p.maxAcquireWaiters {
+				atomic.AddInt32(&p.acquireWaiters, -1)
+				return nil, ErrPoolExhausted
+			}
+			defer atomic.AddInt32(&p.acquireWaiters, -1)
+		}
+	}
+
+	if tag != "" {
+		if res := p.takeIdleTagged(tag); res != nil && p.checkAcquired(ctx, res) {
+			return res.Value().getConn(p, res), nil
+		}
+	}
+
 	for {
-		res, err := p.p.Acquire(ctx)
+		res, err := p.acquireResource(ctx)
 		if err != nil {
 			return nil, err
 		}
 
-		cr := res.Value()
+		if p.checkAcquired(ctx, res) {
+			return res.Value().getConn(p, res), nil
+		}
+	}
+}
 
-		if res.IdleDuration() > time.Second {
-			err := cr.conn.Ping(ctx)
-			if err != nil {
-				res.Destroy()
-				continue
-			}
+// takeIdleTagged takes the most recently used connection tagged with tag from p.idleTagged. Any connection matches an
+// empty tag. It returns nil if there is none.
+func (p *Pool) takeIdleTagged(tag string) *puddle.Resource[*connResource] {
+	p.idleTaggedMux.Lock()
+	defer p.idleTaggedMux.Unlock()
+
+	return p.takeIdleTaggedLocked(tag)
+}
+
+func (p *Pool) takeIdleTaggedLocked(tag string) *puddle.Resource[*connResource] {
+	for i := len(p.idleTagged) - 1; i >= 0; i-- {
+		res := p.idleTagged[i]
+		if _, ok := res.Value().tags[tag]; ok || tag == "" {
+			copy(p.idleTagged[i:], p.idleTagged[i+1:])
+			p.idleTagged[len(p.idleTagged)-1] = nil
+			p.idleTagged = p.idleTagged[:len(p.idleTagged)-1]
+			return res
 		}
+	}
+	return nil
+}
+
+// takeAllIdleTagged takes all connections from p.idleTagged.
+func (p *Pool) takeAllIdleTagged() []*puddle.Resource[*connResource] {
+	p.idleTaggedMux.Lock()
+	defer p.idleTaggedMux.Unlock()
+
+	resources := p.idleTagged
+	p.idleTagged = nil
+	return resources
+}
+
+// putIdleTagged adds res to p.idleTagged. It returns false if res cannot be kept there because the pool is closed or
+// was reset, or because an acquire may be waiting for a connection.
+func (p *Pool) putIdleTagged(res *puddle.Resource[*connResource]) bool {
+	p.idleTaggedMux.Lock()
+	defer p.idleTaggedMux.Unlock()
+
+	select {
+	case <-p.closeChan:
+		return false
+	default:
+	}
+
+	if p.acquiringCount > 0 || res.Value().resetCount != atomic.LoadInt64(&p.resetCount) {
+		return false
+	}
 
-		if p.beforeAcquire == nil || p.beforeAcquire(ctx, cr.conn) {
-			return cr.getConn(p, res), nil
+	p.idleTagged = append(p.idleTagged, res)
+	return true
+}
+
+// releaseResource returns res to the pool. A tagged connection is put in p.idleTagged if possible.
+func (p *Pool) releaseResource(res *puddle.Resource[*connResource]) {
+	cr := res.Value()
+	if len(cr.tags) > 0 {
+		cr.idleTaggedTime = time.Now()
+		if p.putIdleTagged(res) {
+			return
 		}
+	}
 
-		res.Destroy()
+	cr.idleTaggedTime = time.Time{}
+	res.Release()
+}
+
+// releaseUnused returns res, which was taken while idle, to where it was taken from without marking it as used.
+func (p *Pool) releaseUnused(res *puddle.Resource[*connResource]) {
+	cr := res.Value()
+	if cr.idleTaggedTime.IsZero() {
+		res.ReleaseUnused()
+		return
+	}
+
+	if !p.putIdleTagged(res) {
+		cr.idleTaggedTime = time.Time{}
+		res.Release()
+	}
+}
+
+// idleDuration returns how long the connection of res was idle before it was acquired.
+func idleDuration(res *puddle.Resource[*connResource]) time.Duration {
+	if t := res.Value().idleTaggedTime; !t.IsZero() {
+		return time.Since(t)
 	}
+	return res.IdleDuration()
+}
+
+// checkAcquired checks that the connection of the just acquired res is usable and prepares it for use. If it is not
+// usable res is destroyed and false is returned.
+func (p *Pool) checkAcquired(ctx context.Context, res *puddle.Resource[*connResource]) bool {
+	cr := res.Value()
+
+	if p.acquireValidation != AcquireValidationNone && idleDuration(res) >= p.acquireValidationIdle {
+		var err error
+		if p.acquireValidation == AcquireValidationCheckConn {
+			err = cr.conn.PgConn().CheckConn()
+		} else {
+			err = cr.conn.Ping(ctx)
+		}
+		if err != nil {
+			res.Destroy()
+			return false
+		}
+	}
+
+	return p.prepareAcquired(ctx, res)
+}
+
+// prepareAcquired prepares the connection of the just acquired res for use. If that fails or BeforeAcquire rejects the
+// connection res is destroyed and false is returned.
+func (p *Pool) prepareAcquired(ctx context.Context, res *puddle.Resource[*connResource]) bool {
+	cr := res.Value()
+
+	if preparedStmtsGen := atomic.LoadInt64(&p.preparedStmtsGen); cr.preparedStmtsGen != preparedStmtsGen {
+		err := cr.conn.DeallocateAll(ctx)
+		if err == nil {
+			err = p.prepareStatements(ctx, cr.conn)
+		}
+		if err != nil {
+			res.Destroy()
+			return false
+		}
+		cr.preparedStmtsGen = preparedStmtsGen
+	}
+
+	if p.beforeAcquire == nil || p.beforeAcquire(ctx, cr.conn) {
+		return true
+	}
+
+	res.Destroy()
+	return false
+}
+
+// prepareStatements prepares Config.PreparedStatements on conn.
+func (p *Pool) prepareStatements(ctx context.Context, conn *pgx.Conn) error {
+	for name, sql := range p.config.PreparedStatements {
+		_, err := conn.Prepare(ctx, name, sql)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InvalidatePreparedStatements causes every connection to deallocate all of its prepared statements and to prepare
+// Config.PreparedStatements again the next time it is acquired. This also clears the statement caches of the
+// connections. Call it after a schema change that invalidates prepared statements.
+func (p *Pool) InvalidatePreparedStatements() {
+	atomic.AddInt64(&p.preparedStmtsGen, 1)
+}
+
+// acquireResource acquires a resource from the underlying pool applying p.acquireTimeout. A connection from
+// p.idleTagged is used if the underlying pool has no idle connection.
+func (p *Pool) acquireResource(ctx context.Context) (*puddle.Resource[*connResource], error) {
+	p.idleTaggedMux.Lock()
+	if len(p.idleTagged) > 0 && p.p.Stat().IdleResources() == 0 {
+		res := p.takeIdleTaggedLocked("")
+		p.idleTaggedMux.Unlock()
+		return res, nil
+	}
+	p.acquiringCount++
+	p.idleTaggedMux.Unlock()
+
+	defer func() {
+		p.idleTaggedMux.Lock()
+		p.acquiringCount--
+		p.idleTaggedMux.Unlock()
+	}()
+
+	if p.acquireTimeout <= 0 {
+		return p.p.Acquire(ctx)
+	}
+
+	acquireCtx, cancel := context.WithTimeout(ctx, p.acquireTimeout)
+	defer cancel()
+
+	res, err := p.p.Acquire(acquireCtx)
+	if err != nil && ctx.Err() == nil && acquireCtx.Err() != nil {
+		return nil, fmt.Errorf("%w: no connection available within %v", ErrPoolExhausted, p.acquireTimeout)
+	}
+	return res, err
 }
 
 // AcquireFunc acquires a *Conn and calls f with that *Conn. ctx will only affect the Acquire. It has no effect on the
@@ -559,16 +1030,15 @@ func (p *Pool) AcquireFunc(ctx context.Context, f func(*Conn) error) error {
 }
 
 // AcquireAllIdle atomically acquires all currently idle connections. Its intended use is for health check and
-// keep-alive functionality. It does not update pool statistics.
+// keep-alive functionality. It does not update pool statistics. Like in Acquire, invalidated Config.PreparedStatements
+// are prepared again and BeforeAcquire is called. Config.AcquireValidation is not applied. Connections that fail are
+// destroyed and not returned.
 func (p *Pool) AcquireAllIdle(ctx context.Context) []*Conn {
-	resources := p.p.AcquireAllIdle()
+	resources := append(p.p.AcquireAllIdle(), p.takeAllIdleTagged()...)
 	conns := make([]*Conn, 0, len(resources))
 	for _, res := range resources {
-		cr := res.Value()
-		if p.beforeAcquire == nil || p.beforeAcquire(ctx, cr.conn) {
-			conns = append(conns, cr.getConn(p, res))
-		} else {
-			res.Destroy()
+		if p.prepareAcquired(ctx, res) {
+			conns = append(conns, res.Value().getConn(p, res))
 		}
 	}
 
@@ -581,7 +1051,11 @@ func (p *Pool) AcquireAllIdle(ctx context.Context) []*Conn {
 // It is safe to reset a pool while connections are checked out. Those connections will be closed when they are returned
 // to the pool.
 func (p *Pool) Reset() {
+	atomic.AddInt64(&p.resetCount, 1)
 	p.p.Reset()
+	for _, res := range p.takeAllIdleTagged() {
+		res.Release()
+	}
 }
 
 // Config returns a copy of config that was used to initialize this pool.
@@ -589,8 +1063,13 @@ func (p *Pool) Config() *Config { return p.config.Copy() }
 
 // Stat returns a pgxpool.Stat struct with a snapshot of Pool statistics.
 func (p *Pool) Stat() *Stat {
+	p.idleTaggedMux.Lock()
+	idleTaggedConns := int32(len(p.idleTagged))
+	p.idleTaggedMux.Unlock()
+
 	return &Stat{
 		s:                    p.p.Stat(),
+		idleTaggedConns:      idleTaggedConns,
 		newConnsCount:        atomic.LoadInt64(&p.newConnsCount),
 		lifetimeDestroyCount: atomic.LoadInt64(&p.lifetimeDestroyCount),
 		idleDestroyCount:     atomic.LoadInt64(&p.idleDestroyCount),
@@ -0,0 +1,156 @@
+package pgxpool
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrPoolSetClosed is returned by PoolSet.Get after PoolSet.Close has been called.
+var ErrPoolSetClosed = errors.New("pool set closed")
+
+// ErrPoolRemoved is returned by PoolSet.Get when PoolSet.Remove is called for the name while the pool is created.
+var ErrPoolRemoved = errors.New("pool removed from pool set")
+
+// PoolSet is a set of named pools that are created on first use from a template config. Each pool has its own
+// connections and limits. This is useful for multi-tenant applications that need a separate pool for each tenant,
+// search_path, or role.
+type PoolSet struct {
+	template  *Config
+	configure func(name string, config *Config) error
+
+	mux    sync.Mutex
+	pools  map[string]*poolSetEntry
+	closed bool
+}
+
+type poolSetEntry struct {
+	ready chan struct{} // closed when pool and err are set
+	pool  *Pool
+	err   error
+}
+
+// NewPoolSet creates a PoolSet. template must have been created by [ParseConfig]. The pool for a name is created with
+// a copy of template that is passed to configure first. configure can change any setting for the name, for example
+// ConnConfig.User, ConnConfig.RuntimeParams["search_path"], or MaxConns. configure may be nil.
+func NewPoolSet(template *Config, configure func(name string, config *Config) error) *PoolSet {
+	return &PoolSet{
+		template:  template.Copy(),
+		configure: configure,
+		pools:     make(map[string]*poolSetEntry),
+	}
+}
+
+// Get returns the pool for name. If it does not exist yet, it is created. Concurrent calls for the same name share the
+// same pool. If creating the pool fails, the error is returned and the next call for name tries again. If Remove or
+// Close is called while the pool is created, the new pool is closed and ErrPoolRemoved or ErrPoolSetClosed is returned.
+func (ps *PoolSet) Get(ctx context.Context, name string) (*Pool, error) {
+	ps.mux.Lock()
+	if ps.closed {
+		ps.mux.Unlock()
+		return nil, ErrPoolSetClosed
+	}
+	entry, ok := ps.pools[name]
+	if !ok {
+		entry = &poolSetEntry{ready: make(chan struct{})}
+		ps.pools[name] = entry
+	}
+	ps.mux.Unlock()
+
+	if ok {
+		select {
+		case <-entry.ready:
+			return entry.pool, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	entry.pool, entry.err = ps.newPool(ctx, name)
+
+	var removedPool *Pool
+	ps.mux.Lock()
+	if entry.err != nil {
+		if ps.pools[name] == entry {
+			delete(ps.pools, name)
+		}
+	} else if ps.pools[name] != entry {
+		// Close or Remove was called while the pool was created.
+		removedPool = entry.pool
+		entry.pool, entry.err = nil, ErrPoolRemoved
+		if ps.closed {
+			entry.err = ErrPoolSetClosed
+		}
+	}
+	ps.mux.Unlock()
+
+	if removedPool != nil {
+		removedPool.Close()
+	}
+	close(entry.ready)
+
+	return entry.pool, entry.err
+}
+
+func (ps *PoolSet) newPool(ctx context.Context, name string) (*Pool, error) {
+	config := ps.template.Copy()
+	if ps.configure != nil {
+		err := ps.configure(name, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewWithConfig(ctx, config)
+}
+
+// Names returns the names of the pools that have been created in sorted order.
+func (ps *PoolSet) Names() []string {
+	ps.mux.Lock()
+	defer ps.mux.Unlock()
+
+	names := make([]string, 0, len(ps.pools))
+	for name := range ps.pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Remove closes the pool for name and removes it from the set. A later call to Get creates a new pool. Like Pool.Close,
+// Remove blocks until all connections of the pool are returned and closed.
+func (ps *PoolSet) Remove(name string) {
+	ps.mux.Lock()
+	entry, ok := ps.pools[name]
+	delete(ps.pools, name)
+	ps.mux.Unlock()
+
+	if ok {
+		<-entry.ready
+		if entry.pool != nil {
+			entry.pool.Close()
+		}
+	}
+}
+
+// Close closes all pools and causes future calls to Get to fail with ErrPoolSetClosed. It blocks until all pools are
+// closed.
+func (ps *PoolSet) Close() {
+	ps.mux.Lock()
+	ps.closed = true
+	entries := make([]*poolSetEntry, 0, len(ps.pools))
+	for _, entry := range ps.pools {
+		entries = append(entries, entry)
+	}
+	ps.pools = make(map[string]*poolSetEntry)
+	ps.mux.Unlock()
+
+	for _, entry := range entries {
+		<-entry.ready
+		if entry.pool != nil {
+			entry.pool.Close()
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package pgxpool_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolSet(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	template, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+
+	ps := pgxpool.NewPoolSet(template, func(name string, config *pgxpool.Config) error {
+		config.MaxConns = 2
+		config.ConnConfig.RuntimeParams["application_name"] = "tenant_" + name
+		return nil
+	})
+	defer ps.Close()
+
+	for _, name := range []string{"a", "b"} {
+		pool, err := ps.Get(ctx, name)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, pool.Config().MaxConns)
+
+		var applicationName string
+		err = pool.QueryRow(ctx, "select current_setting('application_name')").Scan(&applicationName)
+		require.NoError(t, err)
+		require.Equal(t, "tenant_"+name, applicationName)
+	}
+
+	a1, err := ps.Get(ctx, "a")
+	require.NoError(t, err)
+	a2, err := ps.Get(ctx, "a")
+	require.NoError(t, err)
+	require.Same(t, a1, a2)
+	require.Equal(t, []string{"a", "b"}, ps.Names())
+
+	ps.Remove("a")
+	require.Equal(t, []string{"b"}, ps.Names())
+	a3, err := ps.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NotSame(t, a1, a3)
+}
+
+func TestPoolSetRemoveWhileCreating(t *testing.T) {
+	t.Parallel()
+
+	template, err := pgxpool.ParseConfig("")
+	require.NoError(t, err)
+
+	var ps *pgxpool.PoolSet
+	ps = pgxpool.NewPoolSet(template, func(name string, config *pgxpool.Config) error {
+		// Remove blocks until the pool is created so it must run concurrently.
+		go ps.Remove(name)
+		require.Eventually(t, func() bool { return len(ps.Names()) == 0 }, 10*time.Second, time.Millisecond)
+		return nil
+	})
+	defer ps.Close()
+
+	pool, err := ps.Get(context.Background(), "a")
+	require.ErrorIs(t, err, pgxpool.ErrPoolRemoved)
+	require.Nil(t, pool)
+	require.Empty(t, ps.Names())
+}
+
+func TestPoolSetConfigureError(t *testing.T) {
+	t.Parallel()
+
+	template, err := pgxpool.ParseConfig("")
+	require.NoError(t, err)
+
+	errBadTenant := errors.New("bad tenant")
+	ps := pgxpool.NewPoolSet(template, func(name string, config *pgxpool.Config) error {
+		if name == "bad" {
+			return errBadTenant
+		}
+		return nil
+	})
+
+	_, err = ps.Get(context.Background(), "bad")
+	require.ErrorIs(t, err, errBadTenant)
+	require.Empty(t, ps.Names())
+
+	// Pools are created without connecting.
+	_, err = ps.Get(context.Background(), "good")
+	require.NoError(t, err)
+	require.Equal(t, []string{"good"}, ps.Names())
+
+	ps.Close()
+	_, err = ps.Get(context.Background(), "good")
+	require.ErrorIs(t, err, pgxpool.ErrPoolSetClosed)
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
+	"strconv"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/pgxtest"
 	"github.com/stretchr/testify/assert"
@@ -43,12 +46,32 @@ func TestConnectConfig(t *testing.T) {
 func TestParseConfigExtractsPoolArguments(t *testing.T) {
 	t.Parallel()
 
-	config, err := pgxpool.ParseConfig("pool_max_conns=42 pool_min_conns=1")
+	config, err := pgxpool.ParseConfig("pool_max_conns=42 pool_min_conns=1 pool_acquire_timeout=5s pool_max_acquire_waiters=10 pool_endpoint_quarantine=1s pool_max_endpoint_quarantine=1m pool_acquire_validation=check_conn pool_acquire_validation_idle_time=5ms")
 	assert.NoError(t, err)
 	assert.EqualValues(t, 42, config.MaxConns)
 	assert.EqualValues(t, 1, config.MinConns)
+	assert.EqualValues(t, 5*time.Second, config.AcquireTimeout)
+	assert.EqualValues(t, 10, config.MaxAcquireWaiters)
+	assert.EqualValues(t, time.Second, config.EndpointQuarantine)
+	assert.EqualValues(t, time.Minute, config.MaxEndpointQuarantine)
+	assert.Equal(t, pgxpool.AcquireValidationCheckConn, config.AcquireValidation)
+	assert.EqualValues(t, 5*time.Millisecond, config.AcquireValidationIdleTime)
 	assert.NotContains(t, config.ConnConfig.Config.RuntimeParams, "pool_max_conns")
 	assert.NotContains(t, config.ConnConfig.Config.RuntimeParams, "pool_min_conns")
+	assert.NotContains(t, config.ConnConfig.Config.RuntimeParams, "pool_acquire_timeout")
+	assert.NotContains(t, config.ConnConfig.Config.RuntimeParams, "pool_max_acquire_waiters")
+	assert.NotContains(t, config.ConnConfig.Config.RuntimeParams, "pool_endpoint_quarantine")
+	assert.NotContains(t, config.ConnConfig.Config.RuntimeParams, "pool_max_endpoint_quarantine")
+	assert.NotContains(t, config.ConnConfig.Config.RuntimeParams, "pool_acquire_validation")
+	assert.NotContains(t, config.ConnConfig.Config.RuntimeParams, "pool_acquire_validation_idle_time")
+
+	config, err = pgxpool.ParseConfig("")
+	assert.NoError(t, err)
+	assert.Equal(t, pgxpool.AcquireValidationPing, config.AcquireValidation)
+	assert.EqualValues(t, time.Second, config.AcquireValidationIdleTime)
+
+	_, err = pgxpool.ParseConfig("pool_acquire_validation=bogus")
+	assert.Error(t, err)
 }
 
 func TestConstructorIgnoresContext(t *testing.T) {
@@ -77,6 +100,40 @@ func TestConstructorIgnoresContext(t *testing.T) {
 	assert.EqualValues(t, 1, pool.Stat().TotalConns())
 }
 
+func TestNewWithConfigPingOnCreate(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	config.PingOnCreate = true
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer pool.Close()
+	require.EqualValues(t, 1, pool.Stat().NewConnsCount())
+
+	// Nothing listens on this port.
+	ln, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(t, err)
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	config, err = pgxpool.ParseConfig(fmt.Sprintf("host=127.0.0.1 port=%d sslmode=disable", port))
+	require.NoError(t, err)
+
+	pool, err = pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	pool.Close()
+
+	config.PingOnCreate = true
+	pool, err = pgxpool.NewWithConfig(ctx, config)
+	require.Error(t, err)
+	require.Nil(t, pool)
+}
+
 func TestConnectConfigRequiresConnConfigFromParseConfig(t *testing.T) {
 	t.Parallel()
 
@@ -203,6 +260,411 @@ func TestPoolAcquireChecksIdleConns(t *testing.T) {
 	require.NotContains(t, pids, cPID)
 }
 
+func TestPoolAcquireTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	config.MaxConns = 1
+	config.AcquireTimeout = 100 * time.Millisecond
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	c, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+
+	_, err = pool.Acquire(ctx)
+	require.ErrorIs(t, err, pgxpool.ErrPoolExhausted)
+
+	c.Release()
+
+	c, err = pool.Acquire(ctx)
+	require.NoError(t, err)
+	c.Release()
+}
+
+func TestPoolMaxAcquireWaiters(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	config.MaxConns = 1
+	config.MaxAcquireWaiters = 1
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	c, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+
+	waiterErrChan := make(chan error)
+	go func() {
+		c, err := pool.Acquire(ctx)
+		if err == nil {
+			c.Release()
+		}
+		waiterErrChan <- err
+	}()
+
+	// Give the waiter time to start waiting.
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = pool.Acquire(ctx)
+	require.ErrorIs(t, err, pgxpool.ErrPoolExhausted)
+
+	c.Release()
+	require.NoError(t, <-waiterErrChan)
+}
+
+func TestPoolAcquireValidation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	for _, tt := range []struct {
+		validation  pgxpool.AcquireValidation
+		expectAlive bool
+	}{
+		{pgxpool.AcquireValidationPing, true},
+		{pgxpool.AcquireValidationNone, false},
+	} {
+		config, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+		require.NoError(t, err)
+		config.MaxConns = 1
+		config.AcquireValidation = tt.validation
+		config.AcquireValidationIdleTime = 0
+
+		pool, err := pgxpool.NewWithConfig(ctx, config)
+		require.NoError(t, err)
+
+		c, err := pool.Acquire(ctx)
+		require.NoError(t, err)
+		pid := c.Conn().PgConn().PID()
+		c.Release()
+
+		conn, err := pgx.Connect(ctx, os.Getenv("PGX_TEST_DATABASE"))
+		require.NoError(t, err)
+		_, err = conn.Exec(ctx, "select pg_terminate_backend($1)", pid)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			var n int
+			err := conn.QueryRow(ctx, "select count(*) from pg_stat_activity where pid = $1", pid).Scan(&n)
+			return err == nil && n == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		conn.Close(ctx)
+
+		c, err = pool.Acquire(ctx)
+		require.NoError(t, err)
+		_, err = c.Exec(ctx, "select 1")
+		if tt.expectAlive {
+			require.NoError(t, err)
+			require.NotEqual(t, pid, c.Conn().PgConn().PID())
+		} else {
+			require.Error(t, err)
+		}
+		c.Release()
+		pool.Close()
+	}
+}
+
+func TestPoolLongRunningAcquires(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	config.TrackAcquires = true
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	c, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+
+	lras := pool.LongRunningAcquires(0)
+	require.Len(t, lras, 1)
+	require.Equal(t, c.Conn().PgConn().PID(), lras[0].PID)
+	require.Contains(t, string(lras[0].Stack), "TestPoolLongRunningAcquires")
+	require.Empty(t, pool.LongRunningAcquires(time.Hour))
+
+	c.Release()
+	require.Empty(t, pool.LongRunningAcquires(0))
+}
+
+func TestPoolLongRunningAcquireHandler(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	config.LongRunningAcquireThreshold = 50 * time.Millisecond
+	_, err = pgxpool.NewWithConfig(ctx, config)
+	require.EqualError(t, err, "LongRunningAcquireHandler is required when LongRunningAcquireThreshold is set")
+
+	reported := make(chan pgxpool.LongRunningAcquire, 10)
+	config.LongRunningAcquireHandler = func(lra pgxpool.LongRunningAcquire) {
+		reported <- lra
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	c, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	defer c.Release()
+
+	select {
+	case lra := <-reported:
+		require.Equal(t, c.Conn().PgConn().PID(), lra.PID)
+	case <-ctx.Done():
+		t.Fatal("long running acquire was not reported")
+	}
+
+	// Each acquire is only reported once.
+	time.Sleep(200 * time.Millisecond)
+	require.Empty(t, reported)
+}
+
+func TestPoolAcquireTagged(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	config.MaxConns = 2
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	c1, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	c2, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+
+	_, err = c1.Exec(ctx, "create temporary table t (id int)")
+	require.NoError(t, err)
+	c1.SetTag("has_temp_table")
+	require.True(t, c1.HasTag("has_temp_table"))
+	require.False(t, c2.HasTag("has_temp_table"))
+	pid := c1.Conn().PgConn().PID()
+	c1.Release()
+	c2.Release()
+
+	for i := 0; i < 5; i++ {
+		c, err := pool.AcquireTagged(ctx, "has_temp_table")
+		require.NoError(t, err)
+		require.True(t, c.HasTag("has_temp_table"))
+		require.Equal(t, pid, c.Conn().PgConn().PID())
+		_, err = c.Exec(ctx, "insert into t values (1)")
+		require.NoError(t, err)
+		c.Release()
+	}
+
+	stat := pool.Stat()
+	require.EqualValues(t, 2, stat.IdleConns())
+	require.EqualValues(t, 0, stat.AcquiredConns())
+
+	// Acquire uses the idle tagged connection when no other connection is idle.
+	c1, err = pool.Acquire(ctx)
+	require.NoError(t, err)
+	c2, err = pool.Acquire(ctx)
+	require.NoError(t, err)
+	require.True(t, c1.HasTag("has_temp_table") != c2.HasTag("has_temp_table"))
+	c1.Release()
+	c2.Release()
+
+	c, err := pool.AcquireTagged(ctx, "unknown")
+	require.NoError(t, err)
+	require.False(t, c.HasTag("unknown"))
+	c.Release()
+
+	c, err = pool.AcquireTagged(ctx, "has_temp_table")
+	require.NoError(t, err)
+	c.RemoveTag("has_temp_table")
+	require.False(t, c.HasTag("has_temp_table"))
+	c.Release()
+}
+
+func TestPoolEndpointQuarantine(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	// Reserve a port that nothing listens on.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	deadPort := uint16(ln.Addr().(*net.TCPAddr).Port)
+	ln.Close()
+
+	config, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	if len(config.ConnConfig.Fallbacks) > 0 {
+		t.Skip("test requires a single host PGX_TEST_DATABASE")
+	}
+	config.EndpointQuarantine = time.Minute
+
+	// Make the dead host the first host.
+	config.ConnConfig.Fallbacks = []*pgconn.FallbackConfig{{
+		Host:      config.ConnConfig.Host,
+		Port:      config.ConnConfig.Port,
+		TLSConfig: config.ConnConfig.TLSConfig,
+	}}
+	config.ConnConfig.Host = "127.0.0.1"
+	config.ConnConfig.Port = deadPort
+
+	var deadDials int32
+	dialFunc := config.ConnConfig.DialFunc
+	config.ConnConfig.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if addr == net.JoinHostPort("127.0.0.1", strconv.Itoa(int(deadPort))) {
+			atomic.AddInt32(&deadDials, 1)
+		}
+		return dialFunc(ctx, network, addr)
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	for i := 0; i < 3; i++ {
+		c, err := pool.Acquire(ctx)
+		require.NoError(t, err)
+		defer c.Release()
+	}
+
+	require.EqualValues(t, 1, atomic.LoadInt32(&deadDials), "dead host should only be tried once")
+
+	stats := pool.EndpointStats()
+	var deadStat *pgxpool.EndpointStat
+	for i := range stats {
+		if stats[i].Host == "127.0.0.1" && stats[i].Port == deadPort {
+			deadStat = &stats[i]
+		}
+	}
+	require.NotNil(t, deadStat)
+	require.Equal(t, 1, deadStat.ConsecutiveFailures)
+	require.Error(t, deadStat.LastError)
+	require.True(t, deadStat.Quarantined(time.Now()))
+}
+
+func TestPoolPreparedStatements(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	config.MaxConns = 1
+	config.PreparedStatements = map[string]string{"add_one": "select $1::int8 + 1"}
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	var n int64
+	err = pool.QueryRow(ctx, "add_one", 1).Scan(&n)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+
+	c, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	_, err = c.Conn().Prepare(ctx, "other", "select 1")
+	require.NoError(t, err)
+	c.Release()
+
+	pool.InvalidatePreparedStatements()
+
+	c, err = pool.Acquire(ctx)
+	require.NoError(t, err)
+	rows, _ := c.Query(ctx, "select name from pg_prepared_statements where name not like 'stmtcache_%' order by name")
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	require.NoError(t, err)
+	require.Equal(t, []string{"add_one"}, names)
+	c.Release()
+
+	require.EqualValues(t, 1, pool.Stat().NewConnsCount())
+}
+
+func TestPoolShutdown(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+
+	c, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer shutdownCancel()
+	err = pool.Shutdown(shutdownCtx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = pool.Acquire(ctx)
+	require.Error(t, err)
+
+	// The acquired connection can still be used until it is returned.
+	_, err = c.Exec(ctx, "select 1")
+	require.NoError(t, err)
+	c.Release()
+
+	err = pool.Shutdown(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, pool.Stat().TotalConns())
+}
+
+func TestPoolDrain(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	defer pool.Close()
+
+	c1, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	c2, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	c2.Release()
+
+	pool.Drain()
+
+	for pool.Stat().TotalConns() != 1 && ctx.Err() == nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.EqualValues(t, 1, pool.Stat().TotalConns())
+	require.EqualValues(t, 1, pool.Stat().AcquiredConns())
+
+	c1.Release()
+
+	_, err = pool.Exec(ctx, "select 1")
+	require.NoError(t, err)
+}
+
 func TestPoolAcquireFunc(t *testing.T) {
 	t.Parallel()
 
@@ -587,6 +1049,47 @@ func TestPoolBackgroundChecksMaxConnIdleTime(t *testing.T) {
 	assert.EqualValues(t, 1, stats.NewConnsCount())
 }
 
+func TestPoolWarmup(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+
+	config.MinConns = 3
+
+	db, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer db.Close()
+
+	err = db.Warmup(ctx)
+	require.NoError(t, err)
+
+	stats := db.Stat()
+	require.EqualValues(t, 3, stats.IdleConns())
+	require.EqualValues(t, 3, stats.NewConnsCount())
+
+	c, err := db.Acquire(ctx)
+	require.NoError(t, err)
+	err = c.Conn().Close(ctx)
+	require.NoError(t, err)
+	c.Release()
+
+	// Wait for the closed connection to be removed from the pool.
+	for db.Stat().TotalConns() != 2 && ctx.Err() == nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err = db.Warmup(ctx)
+	require.NoError(t, err)
+
+	stats = db.Stat()
+	require.EqualValues(t, 3, stats.IdleConns())
+	require.EqualValues(t, 4, stats.NewConnsCount())
+}
+
 func TestPoolBackgroundChecksMinConns(t *testing.T) {
 	t.Parallel()
 
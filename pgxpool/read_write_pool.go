@@ -0,0 +1,206 @@
+package pgxpool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ReadWritePool routes work between a pool of connections to the primary server of a cluster and a pool of connections
+// to its standby (replica) servers. Exec, Query, QueryRow, SendBatch, CopyFrom, Begin, and read-write transactions use
+// the primary. Read-only transactions use a replica. Primary and Replica give direct access to each pool for anything
+// else.
+type ReadWritePool struct {
+	primary *Pool
+	replica *Pool
+}
+
+// NewReadWritePool creates a ReadWritePool from config. config should list every host of the cluster (e.g.
+// host=pg1,pg2,pg3). Primary connections are established as with target_session_attrs=primary. Replica connections
+// are established as with target_session_attrs=prefer-standby, so reads use the primary when no standby is available.
+// Any ValidateConnect set in config is replaced.
+//
+// A server can change role after a failover. If roleCheckInterval is greater than 0, the role of a connection is
+// checked with pg_is_in_recovery() when it is acquired and its role was last checked at least roleCheckInterval ago.
+// If the role changed since the connection was established, the connection is destroyed and a different connection is
+// acquired.
+func NewReadWritePool(ctx context.Context, config *Config, roleCheckInterval time.Duration) (*ReadWritePool, error) {
+	primaryConfig := config.Copy()
+	primaryConfig.ConnConfig.ValidateConnect = pgconn.ValidateConnectTargetSessionAttrsPrimary
+
+	replicaConfig := config.Copy()
+	replicaConfig.ConnConfig.ValidateConnect = pgconn.ValidateConnectTargetSessionAttrsPreferStandby
+
+	if roleCheckInterval > 0 {
+		newRoleChecker(roleCheckInterval).install(primaryConfig)
+		newRoleChecker(roleCheckInterval).install(replicaConfig)
+	}
+
+	primary, err := NewWithConfig(ctx, primaryConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	replica, err := NewWithConfig(ctx, replicaConfig)
+	if err != nil {
+		primary.Close()
+		return nil, err
+	}
+
+	return &ReadWritePool{primary: primary, replica: replica}, nil
+}
+
+// Primary returns the pool of connections to the primary server.
+func (p *ReadWritePool) Primary() *Pool { return p.primary }
+
+// Replica returns the pool of connections to the standby servers.
+func (p *ReadWritePool) Replica() *Pool { return p.replica }
+
+// AcquirePrimary returns a connection to the primary server.
+func (p *ReadWritePool) AcquirePrimary(ctx context.Context) (*Conn, error) {
+	return p.primary.Acquire(ctx)
+}
+
+// AcquireReplica returns a connection to a standby server or to the primary server if no standby is available.
+func (p *ReadWritePool) AcquireReplica(ctx context.Context) (*Conn, error) {
+	return p.replica.Acquire(ctx)
+}
+
+// Close closes both pools. See Pool.Close.
+func (p *ReadWritePool) Close() {
+	p.primary.Close()
+	p.replica.Close()
+}
+
+// Exec acquires a connection to the primary and executes sql. See Pool.Exec.
+func (p *ReadWritePool) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	return p.primary.Exec(ctx, sql, arguments...)
+}
+
+// Query acquires a connection to the primary and executes sql. See Pool.Query.
+func (p *ReadWritePool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return p.primary.Query(ctx, sql, args...)
+}
+
+// QueryRow acquires a connection to the primary and executes sql. See Pool.QueryRow.
+func (p *ReadWritePool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return p.primary.QueryRow(ctx, sql, args...)
+}
+
+// SendBatch acquires a connection to the primary and sends b. See Pool.SendBatch.
+func (p *ReadWritePool) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return p.primary.SendBatch(ctx, b)
+}
+
+// CopyFrom acquires a connection to the primary and copies rowSrc into tableName. See Pool.CopyFrom.
+func (p *ReadWritePool) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return p.primary.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+// Begin starts a transaction on the primary. See Pool.Begin.
+func (p *ReadWritePool) Begin(ctx context.Context) (pgx.Tx, error) {
+	return p.primary.Begin(ctx)
+}
+
+// BeginTx starts a transaction. If txOptions.AccessMode is pgx.ReadOnly the transaction is started on a replica.
+// Otherwise it is started on the primary. See Pool.BeginTx.
+func (p *ReadWritePool) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	if txOptions.AccessMode == pgx.ReadOnly {
+		return p.replica.BeginTx(ctx, txOptions)
+	}
+	return p.primary.BeginTx(ctx, txOptions)
+}
+
+// Ping acquires a connection from each pool and pings it.
+func (p *ReadWritePool) Ping(ctx context.Context) error {
+	if err := p.primary.Ping(ctx); err != nil {
+		return err
+	}
+	return p.replica.Ping(ctx)
+}
+
+// roleChecker destroys connections whose server changed role since they were established.
+type roleChecker struct {
+	interval time.Duration
+
+	mux   sync.Mutex
+	roles map[*pgx.Conn]*connRole
+}
+
+type connRole struct {
+	inRecovery bool
+	checkedAt  time.Time
+}
+
+func newRoleChecker(interval time.Duration) *roleChecker {
+	return &roleChecker{
+		interval: interval,
+		roles:    make(map[*pgx.Conn]*connRole),
+	}
+}
+
+// install wraps the AfterConnect, BeforeAcquire, and BeforeClose hooks of config.
+func (rc *roleChecker) install(config *Config) {
+	afterConnect := config.AfterConnect
+	config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		if afterConnect != nil {
+			if err := afterConnect(ctx, conn); err != nil {
+				return err
+			}
+		}
+
+		inRecovery, err := queryInRecovery(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		rc.mux.Lock()
+		rc.roles[conn] = &connRole{inRecovery: inRecovery, checkedAt: time.Now()}
+		rc.mux.Unlock()
+		return nil
+	}
+
+	beforeAcquire := config.BeforeAcquire
+	config.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+		if beforeAcquire != nil && !beforeAcquire(ctx, conn) {
+			return false
+		}
+
+		rc.mux.Lock()
+		role := rc.roles[conn]
+		rc.mux.Unlock()
+		if role == nil || time.Since(role.checkedAt) < rc.interval {
+			return true
+		}
+
+		inRecovery, err := queryInRecovery(ctx, conn)
+		if err != nil || inRecovery != role.inRecovery {
+			return false
+		}
+
+		rc.mux.Lock()
+		role.checkedAt = time.Now()
+		rc.mux.Unlock()
+		return true
+	}
+
+	beforeClose := config.BeforeClose
+	config.BeforeClose = func(conn *pgx.Conn) {
+		rc.mux.Lock()
+		delete(rc.roles, conn)
+		rc.mux.Unlock()
+
+		if beforeClose != nil {
+			beforeClose(conn)
+		}
+	}
+}
+
+func queryInRecovery(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	var inRecovery bool
+	err := conn.QueryRow(ctx, "select pg_is_in_recovery()").Scan(&inRecovery)
+	return inRecovery, err
+}
@@ -0,0 +1,69 @@
+package pgxpool_test
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadWritePool(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+
+	var afterConnectCount int32
+	config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		atomic.AddInt32(&afterConnectCount, 1)
+		return nil
+	}
+	config.MaxConns = 1
+
+	// The test server has no standby so replica connections fall back to the primary.
+	pool, err := pgxpool.NewReadWritePool(ctx, config, time.Nanosecond)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	require.NoError(t, pool.Ping(ctx))
+	require.EqualValues(t, 2, atomic.LoadInt32(&afterConnectCount))
+
+	testExec(t, ctx, pool)
+	testQuery(t, ctx, pool)
+	testQueryRow(t, ctx, pool)
+	testSendBatch(t, ctx, pool)
+	testCopyFrom(t, ctx, pool)
+
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, pool.Replica().Stat().AcquiredConns())
+	require.EqualValues(t, 0, pool.Primary().Stat().AcquiredConns())
+	require.NoError(t, tx.Rollback(ctx))
+
+	tx, err = pool.BeginTx(ctx, pgx.TxOptions{})
+	require.NoError(t, err)
+	require.EqualValues(t, 0, pool.Replica().Stat().AcquiredConns())
+	require.EqualValues(t, 1, pool.Primary().Stat().AcquiredConns())
+	require.NoError(t, tx.Rollback(ctx))
+
+	// The role check passes because the role did not change so no new connections are established.
+	for i := 0; i < 3; i++ {
+		c, err := pool.AcquireReplica(ctx)
+		require.NoError(t, err)
+		c.Release()
+
+		c, err = pool.AcquirePrimary(ctx)
+		require.NoError(t, err)
+		c.Release()
+	}
+	require.EqualValues(t, 1, pool.Primary().Stat().NewConnsCount())
+	require.EqualValues(t, 1, pool.Replica().Stat().NewConnsCount())
+}
@@ -82,6 +82,15 @@ func (rows *poolRows) Values() ([]any, error) {
 	return values, err
 }
 
+// ValuesInto implements the ValuesInto method used by pgx.ValuesInto.
+func (rows *poolRows) ValuesInto(dst []any) ([]any, error) {
+	values, err := pgx.ValuesInto(rows.r, dst)
+	if err != nil {
+		rows.Close()
+	}
+	return values, err
+}
+
 func (rows *poolRows) RawValues() [][]byte {
 	return rows.r.RawValues()
 }
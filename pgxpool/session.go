@@ -0,0 +1,293 @@
+package pgxpool
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Session pins one connection of a pool for a sequence of operations that depend on session state such as temporary
+// tables, cursors, advisory locks, or settings. The connection is only returned to the pool by Close, which first
+// resets the session state so it does not leak to other users of the pool.
+//
+// If a Session becomes unreachable without being closed, its connection is closed instead of being returned to the
+// pool. The server then releases the session state. Rows, Tx, and BatchResults returned by a Session keep it
+// reachable. The *pgx.Conn returned by Conn does not.
+//
+// A Session must not be used after Close. Like Conn, it is not safe for concurrent usage.
+type Session struct {
+	c *Conn
+}
+
+// AcquireSession acquires a connection from the pool and pins it to a new Session.
+func (p *Pool) AcquireSession(ctx context.Context) (*Session, error) {
+	c, err := p.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{c: c}
+	runtime.SetFinalizer(s, (*Session).leaked)
+	return s, nil
+}
+
+// leaked closes the connection of a Session that was not closed.
+func (s *Session) leaked() {
+	conn := s.c.Hijack()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		conn.Close(ctx)
+	}()
+}
+
+// Close resets the session state with pgx.Conn.Reset (DISCARD ALL) and returns the connection to the pool.
+// Config.AfterConnect is called again so the session state it sets up is restored. If resetting fails or a transaction
+// is still in progress the connection is closed and the error is returned. It is safe to call Close multiple times.
+func (s *Session) Close(ctx context.Context) error {
+	if s.c == nil {
+		return nil
+	}
+	runtime.SetFinalizer(s, nil)
+
+	c := s.c
+	s.c = nil
+	defer c.Release()
+
+	conn := c.Conn()
+	if conn.IsClosed() {
+		return nil
+	}
+
+	err := conn.Reset(ctx)
+	if err == nil && c.p.afterConnect != nil {
+		err = c.p.afterConnect(ctx, conn)
+	}
+	if err != nil {
+		conn.Close(ctx)
+		return err
+	}
+
+	// Reset deallocated Config.PreparedStatements and the state the tags referred to.
+	cr := c.connResource()
+	cr.preparedStmtsGen = -1
+	cr.tags = nil
+
+	return nil
+}
+
+// Conn returns the underlying *pgx.Conn. It must not be used after the Session is closed.
+func (s *Session) Conn() *pgx.Conn {
+	return s.c.Conn()
+}
+
+// The methods of Session and of the values it returns call runtime.KeepAlive so the finalizer set by AcquireSession
+// cannot close the connection while it is still in use.
+
+func (s *Session) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	defer runtime.KeepAlive(s)
+	return s.c.Exec(ctx, sql, arguments...)
+}
+
+func (s *Session) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	defer runtime.KeepAlive(s)
+	rows, err := s.c.Query(ctx, sql, args...)
+	if err != nil {
+		return rows, err
+	}
+	return &sessionRows{rows: rows, s: s}, nil
+}
+
+func (s *Session) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	defer runtime.KeepAlive(s)
+	return &sessionRow{row: s.c.QueryRow(ctx, sql, args...), s: s}
+}
+
+func (s *Session) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	defer runtime.KeepAlive(s)
+	return &sessionBatchResults{br: s.c.SendBatch(ctx, b), s: s}
+}
+
+func (s *Session) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	defer runtime.KeepAlive(s)
+	return s.c.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+// Begin starts a transaction on the connection of the Session.
+func (s *Session) Begin(ctx context.Context) (pgx.Tx, error) {
+	return s.BeginTx(ctx, pgx.TxOptions{})
+}
+
+// BeginTx starts a transaction on the connection of the Session with txOptions determining the transaction mode.
+func (s *Session) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	defer runtime.KeepAlive(s)
+	tx, err := s.c.BeginTx(ctx, txOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &sessionTx{Tx: tx, s: s}, nil
+}
+
+func (s *Session) Ping(ctx context.Context) error {
+	defer runtime.KeepAlive(s)
+	return s.c.Ping(ctx)
+}
+
+// sessionRows, sessionRow, sessionBatchResults, and sessionTx keep their Session reachable while they are in use.
+
+type sessionRows struct {
+	rows pgx.Rows
+	s    *Session
+}
+
+func (r *sessionRows) Close() {
+	defer runtime.KeepAlive(r.s)
+	r.rows.Close()
+}
+
+func (r *sessionRows) Err() error {
+	return r.rows.Err()
+}
+
+func (r *sessionRows) CommandTag() pgconn.CommandTag {
+	return r.rows.CommandTag()
+}
+
+func (r *sessionRows) FieldDescriptions() []pgconn.FieldDescription {
+	return r.rows.FieldDescriptions()
+}
+
+func (r *sessionRows) RawValues() [][]byte {
+	return r.rows.RawValues()
+}
+
+func (r *sessionRows) Conn() *pgx.Conn {
+	return r.rows.Conn()
+}
+
+func (r *sessionRows) Next() bool {
+	defer runtime.KeepAlive(r.s)
+	return r.rows.Next()
+}
+
+func (r *sessionRows) Scan(dest ...any) error {
+	defer runtime.KeepAlive(r.s)
+	return r.rows.Scan(dest...)
+}
+
+func (r *sessionRows) Values() ([]any, error) {
+	defer runtime.KeepAlive(r.s)
+	return r.rows.Values()
+}
+
+func (r *sessionRows) ValuesInto(dst []any) ([]any, error) {
+	defer runtime.KeepAlive(r.s)
+	return pgx.ValuesInto(r.rows, dst)
+}
+
+type sessionRow struct {
+	row pgx.Row
+	s   *Session
+}
+
+func (r *sessionRow) Scan(dest ...any) error {
+	defer runtime.KeepAlive(r.s)
+	return r.row.Scan(dest...)
+}
+
+type sessionBatchResults struct {
+	br pgx.BatchResults
+	s  *Session
+}
+
+func (br *sessionBatchResults) Exec() (pgconn.CommandTag, error) {
+	defer runtime.KeepAlive(br.s)
+	return br.br.Exec()
+}
+
+func (br *sessionBatchResults) Query() (pgx.Rows, error) {
+	defer runtime.KeepAlive(br.s)
+	rows, err := br.br.Query()
+	if err != nil {
+		return rows, err
+	}
+	return &sessionRows{rows: rows, s: br.s}, nil
+}
+
+func (br *sessionBatchResults) QueryRow() pgx.Row {
+	defer runtime.KeepAlive(br.s)
+	return &sessionRow{row: br.br.QueryRow(), s: br.s}
+}
+
+func (br *sessionBatchResults) Close() error {
+	defer runtime.KeepAlive(br.s)
+	return br.br.Close()
+}
+
+// sessionTx embeds pgx.Tx so that it keeps implementing pgx.Tx if methods are added. Every method that uses the
+// connection is overridden to keep the Session reachable. LargeObjects returned by the transaction do not keep the
+// Session reachable.
+type sessionTx struct {
+	pgx.Tx
+	s *Session
+}
+
+func (tx *sessionTx) UnwrapTx() pgx.Tx {
+	return tx.Tx
+}
+
+func (tx *sessionTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	defer runtime.KeepAlive(tx.s)
+	nested, err := tx.Tx.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &sessionTx{Tx: nested, s: tx.s}, nil
+}
+
+func (tx *sessionTx) Commit(ctx context.Context) error {
+	defer runtime.KeepAlive(tx.s)
+	return tx.Tx.Commit(ctx)
+}
+
+func (tx *sessionTx) Rollback(ctx context.Context) error {
+	defer runtime.KeepAlive(tx.s)
+	return tx.Tx.Rollback(ctx)
+}
+
+func (tx *sessionTx) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	defer runtime.KeepAlive(tx.s)
+	return tx.Tx.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+func (tx *sessionTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	defer runtime.KeepAlive(tx.s)
+	return &sessionBatchResults{br: tx.Tx.SendBatch(ctx, b), s: tx.s}
+}
+
+func (tx *sessionTx) Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error) {
+	defer runtime.KeepAlive(tx.s)
+	return tx.Tx.Prepare(ctx, name, sql)
+}
+
+func (tx *sessionTx) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	defer runtime.KeepAlive(tx.s)
+	return tx.Tx.Exec(ctx, sql, arguments...)
+}
+
+func (tx *sessionTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	defer runtime.KeepAlive(tx.s)
+	rows, err := tx.Tx.Query(ctx, sql, args...)
+	if err != nil {
+		return rows, err
+	}
+	return &sessionRows{rows: rows, s: tx.s}, nil
+}
+
+func (tx *sessionTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	defer runtime.KeepAlive(tx.s)
+	return &sessionRow{row: tx.Tx.QueryRow(ctx, sql, args...), s: tx.s}
+}
@@ -0,0 +1,82 @@
+package pgxpool_test
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	config, err := pgxpool.ParseConfig(os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	config.MaxConns = 1
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	s, err := pool.AcquireSession(ctx)
+	require.NoError(t, err)
+
+	_, err = s.Exec(ctx, "create temporary table t (id int)")
+	require.NoError(t, err)
+	_, err = s.Exec(ctx, "insert into t values (1), (2)")
+	require.NoError(t, err)
+
+	tx, err := s.Begin(ctx)
+	require.NoError(t, err)
+	nestedTx, err := tx.Begin(ctx)
+	require.NoError(t, err)
+	_, err = nestedTx.Exec(ctx, "insert into t values (3)")
+	require.NoError(t, err)
+	require.EqualError(t, pgx.PrepareTransaction(ctx, nestedTx, "pgxpool session test"), "cannot prepare a pseudo nested transaction")
+	require.NoError(t, nestedTx.Commit(ctx))
+	require.NoError(t, tx.Commit(ctx))
+
+	var n int
+	err = s.QueryRow(ctx, "select count(*) from t").Scan(&n)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	require.NoError(t, s.Close(ctx))
+	require.NoError(t, s.Close(ctx))
+
+	// The temporary table must not leak to the next user of the connection.
+	err = pool.QueryRow(ctx, "select count(*) from pg_tables where tablename = 't' and schemaname like 'pg_temp%'").Scan(&n)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+	require.EqualValues(t, 1, pool.Stat().TotalConns())
+}
+
+func TestSessionLeaked(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, os.Getenv("PGX_TEST_DATABASE"))
+	require.NoError(t, err)
+	defer pool.Close()
+
+	func() {
+		s, err := pool.AcquireSession(ctx)
+		require.NoError(t, err)
+		require.NoError(t, s.Ping(ctx))
+	}()
+
+	require.Eventually(t, func() bool {
+		runtime.GC()
+		return pool.Stat().TotalConns() == 0
+	}, 10*time.Second, 10*time.Millisecond)
+}
@@ -9,6 +9,7 @@ import (
 // Stat is a snapshot of Pool statistics.
 type Stat struct {
 	s                    *puddle.Stat
+	idleTaggedConns      int32 // idle tagged connections are acquired from s
 	newConnsCount        int64
 	lifetimeDestroyCount int64
 	idleDestroyCount     int64
@@ -27,7 +28,7 @@ func (s *Stat) AcquireDuration() time.Duration {
 
 // AcquiredConns returns the number of currently acquired connections in the pool.
 func (s *Stat) AcquiredConns() int32 {
-	return s.s.AcquiredResources()
+	return s.s.AcquiredResources() - s.idleTaggedConns
 }
 
 // CanceledAcquireCount returns the cumulative count of acquires from the pool
@@ -51,7 +52,7 @@ func (s *Stat) EmptyAcquireCount() int64 {
 
 // IdleConns returns the number of currently idle conns in the pool.
 func (s *Stat) IdleConns() int32 {
-	return s.s.IdleResources()
+	return s.s.IdleResources() + s.idleTaggedConns
 }
 
 // MaxConns returns the maximum size of the pool.
@@ -43,6 +43,23 @@ func (tx *Tx) Rollback(ctx context.Context) error {
 	return err
 }
 
+// UnwrapTx returns the underlying pgx.Tx. pgx.PrepareTransaction and pgx.AddTxHooks use it to find the real
+// transaction.
+func (tx *Tx) UnwrapTx() pgx.Tx {
+	return tx.t
+}
+
+// PrepareTransaction prepares the transaction for two-phase commit and returns the associated connection back to the
+// Pool. See pgx.PrepareTransaction.
+func (tx *Tx) PrepareTransaction(ctx context.Context, gid string) error {
+	err := pgx.PrepareTransaction(ctx, tx.t, gid)
+	if tx.c != nil {
+		tx.c.Release()
+		tx.c = nil
+	}
+	return err
+}
+
 func (tx *Tx) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
 	return tx.t.CopyFrom(ctx, tableName, columnNames, rowSrc)
 }
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -420,7 +421,7 @@ func TestConnQueryReadWrongTypeError(t *testing.T) {
 		t.Fatal("Expected Rows to have an error after an improper read but it didn't")
 	}
 
-	if rows.Err().Error() != "can't scan into dest[0]: cannot scan int4 (OID 23) in binary format into *time.Time" {
+	if rows.Err().Error() != "can't scan into dest[0] (col: n): cannot scan int4 (OID 23) in binary format into *time.Time" {
 		t.Fatalf("Expected different Rows.Err(): %v", rows.Err())
 	}
 
@@ -1010,6 +1011,38 @@ func TestQueryRowErrors(t *testing.T) {
 	}
 }
 
+func TestQueryRowScanArgError(t *testing.T) {
+	t.Parallel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	var n int16
+	err := conn.QueryRow(context.Background(), "select 1, 'Jack'::text as name").Scan(&n, &n)
+	var scanArgErr pgx.ScanArgError
+	require.True(t, errors.As(err, &scanArgErr))
+	require.Equal(t, 1, scanArgErr.ColumnIndex)
+	require.Equal(t, "name", scanArgErr.FieldName)
+	require.EqualValues(t, pgtype.TextOID, scanArgErr.DataTypeOID)
+	require.Equal(t, reflect.TypeOf(&n), scanArgErr.DestType)
+
+	type person struct {
+		Name string
+		Age  int16
+	}
+
+	rows, _ := conn.Query(context.Background(), "select 'Jack'::text as name, 'unknown'::text as age")
+	_, err = pgx.CollectRows(rows, pgx.RowToStructByName[person])
+	scanArgErr = pgx.ScanArgError{}
+	require.True(t, errors.As(err, &scanArgErr))
+	require.Equal(t, 1, scanArgErr.ColumnIndex)
+	require.Equal(t, "age", scanArgErr.FieldName)
+	require.EqualValues(t, pgtype.TextOID, scanArgErr.DataTypeOID)
+	require.Equal(t, reflect.TypeOf(new(int16)), scanArgErr.DestType)
+
+	ensureConnValid(t, conn)
+}
+
 func TestQueryRowNoResults(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,126 @@
+package pgx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrReadOnly is returned (wrapped) when a connection with ConnConfig.ReadOnly set rejects a statement client-side.
+var ErrReadOnly = errors.New("read-only connection")
+
+// mutatingKeywords are the leading keywords of statements that are rejected by a read-only connection.
+var mutatingKeywords = map[string]struct{}{
+	"alter":    {},
+	"cluster":  {},
+	"comment":  {},
+	"create":   {},
+	"delete":   {},
+	"drop":     {},
+	"grant":    {},
+	"insert":   {},
+	"merge":    {},
+	"refresh":  {},
+	"reindex":  {},
+	"revoke":   {},
+	"truncate": {},
+	"update":   {},
+	"vacuum":   {},
+}
+
+// checkReadOnly returns an error if c is read-only and sql obviously modifies the database or lifts the read-only
+// restriction of the session. The server enforces default_transaction_read_only for everything else.
+func (c *Conn) checkReadOnly(sql string) error {
+	if !c.config.ReadOnly {
+		return nil
+	}
+
+	keyword := strings.ToLower(leadingKeyword(sql))
+	if _, ok := mutatingKeywords[keyword]; ok {
+		return fmt.Errorf("%w: cannot execute %s", ErrReadOnly, strings.ToUpper(keyword))
+	}
+
+	if liftsReadOnly(keyword, sql) {
+		return fmt.Errorf("%w: cannot change transaction access mode", ErrReadOnly)
+	}
+
+	return nil
+}
+
+// checkReadOnlySetting returns an error if c is read-only and the run-time parameter name controls the transaction
+// access mode.
+func (c *Conn) checkReadOnlySetting(name string) error {
+	if c.config.ReadOnly && isAccessModeSetting(strings.ToLower(name)) {
+		return fmt.Errorf("%w: cannot change transaction access mode", ErrReadOnly)
+	}
+
+	return nil
+}
+
+// liftsReadOnly returns true if sql, whose lowercase leading keyword is keyword, would allow writes in spite of
+// default_transaction_read_only. e.g. BEGIN READ WRITE, SET default_transaction_read_only = off, SET SESSION
+// CHARACTERISTICS AS TRANSACTION READ WRITE, or select set_config('transaction_read_only', 'off', false).
+func liftsReadOnly(keyword, sql string) bool {
+	normalized := strings.Join(strings.Fields(strings.ToLower(sql)), " ")
+
+	switch keyword {
+	case "begin", "start":
+		return strings.Contains(normalized, "read write")
+	case "set", "reset":
+		return isAccessModeSetting(normalized) ||
+			strings.Contains(normalized, "session characteristics") ||
+			strings.Contains(normalized, "read write")
+	}
+
+	return strings.Contains(normalized, "set_config") && isAccessModeSetting(normalized)
+}
+
+// isAccessModeSetting returns true if s, which must be lowercase, names a run-time parameter that controls the
+// transaction access mode: transaction_read_only or default_transaction_read_only.
+func isAccessModeSetting(s string) bool {
+	return strings.Contains(s, "transaction_read_only")
+}
+
+// leadingKeyword returns the first word of sql skipping leading whitespace, comments, and parentheses.
+func leadingKeyword(sql string) string {
+	for {
+		sql = strings.TrimLeftFunc(sql, func(r rune) bool { return unicode.IsSpace(r) || r == '(' })
+
+		switch {
+		case strings.HasPrefix(sql, "--"):
+			idx := strings.IndexByte(sql, '\n')
+			if idx == -1 {
+				return ""
+			}
+			sql = sql[idx+1:]
+		case strings.HasPrefix(sql, "/*"):
+			depth := 0
+			i := 0
+			for i < len(sql) {
+				if strings.HasPrefix(sql[i:], "/*") {
+					depth++
+					i += 2
+				} else if strings.HasPrefix(sql[i:], "*/") {
+					depth--
+					i += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					i++
+				}
+			}
+			if depth != 0 {
+				return ""
+			}
+			sql = sql[i:]
+		default:
+			end := strings.IndexFunc(sql, func(r rune) bool { return !unicode.IsLetter(r) })
+			if end == -1 {
+				return sql
+			}
+			return sql[:end]
+		}
+	}
+}
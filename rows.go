@@ -57,6 +57,10 @@ type Rows interface {
 	// dest can include pointers to core types, values implementing the Scanner
 	// interface, and nil. nil will skip the value entirely. It is an error to
 	// call Scan without first calling Next() and checking that it returned true.
+	//
+	// If a single pointer to a struct is passed as dest and the row has more than one column, the columns are matched
+	// to the struct fields by name in the same way as RowToStructByName. Destinations created with NamedDest are
+	// matched to columns by name instead of by position.
 	Scan(dest ...any) error
 
 	// Values returns the decoded row values. As with Scan(), it is an error to
@@ -135,16 +139,24 @@ type baseRows struct {
 	scanPlans []pgtype.ScanPlan
 	scanTypes []reflect.Type
 
+	// valueTypes caches the registered type for each column for use by Values. An element is nil if the column's OID is
+	// not registered.
+	valueTypes []*pgtype.Type
+
 	conn              *Conn
 	multiResultReader *pgconn.MultiResultReader
 
 	queryTracer QueryTracer
 	batchTracer BatchTracer
 	ctx         context.Context
+	cancelCtx   context.CancelFunc // releases the context created for DefaultQueryTimeout
 	startTime   time.Time
 	sql         string
 	args        []any
 	rowCount    int
+
+	inBatch  bool // rows of a batch query report their error as a *BatchError
+	batchIdx int  // index of the query in its batch
 }
 
 func (rows *baseRows) FieldDescriptions() []pgconn.FieldDescription {
@@ -173,6 +185,10 @@ func (rows *baseRows) Close() {
 		}
 	}
 
+	if rows.err != nil && rows.inBatch {
+		rows.err = newBatchError(rows.batchIdx, rows.sql, rows.err)
+	}
+
 	if rows.err != nil && rows.conn != nil && rows.sql != "" {
 		if sc := rows.conn.statementCache; sc != nil {
 			sc.Invalidate(rows.sql)
@@ -188,6 +204,10 @@ func (rows *baseRows) Close() {
 	} else if rows.queryTracer != nil {
 		rows.queryTracer.TraceQueryEnd(rows.ctx, rows.conn, TraceQueryEndData{rows.commandTag, rows.err})
 	}
+
+	if rows.cancelCtx != nil {
+		rows.cancelCtx()
+	}
 }
 
 func (rows *baseRows) CommandTag() pgconn.CommandTag {
@@ -243,6 +263,31 @@ func (rows *baseRows) Scan(dest ...any) error {
 			}
 			return err
 		}
+
+		// A single struct destination cannot be scanned positionally into multiple columns, so match the columns to the
+		// struct fields by name instead.
+		if len(fieldDescriptions) > 1 && isPointerToStruct(dest[0]) {
+			if reflect.ValueOf(dest[0]).IsNil() {
+				err := fmt.Errorf("cannot scan into nil %T", dest[0])
+				rows.fatal(err)
+				return err
+			}
+
+			err := (&namedStructRowScanner{ptrToStruct: dest[0]}).ScanRow(rows)
+			if err != nil {
+				rows.fatal(err)
+			}
+			return err
+		}
+	}
+
+	if hasNamedDest(dest) {
+		var err error
+		dest, err = positionalDestsFromNamedDests(fieldDescriptions, dest)
+		if err != nil {
+			rows.fatal(err)
+			return err
+		}
 	}
 
 	if len(fieldDescriptions) != len(dest) {
@@ -272,7 +317,7 @@ func (rows *baseRows) Scan(dest ...any) error {
 
 		err := rows.scanPlans[i].Scan(values[i], dst)
 		if err != nil {
-			err = ScanArgError{ColumnIndex: i, Err: err}
+			err = newScanArgError(&fieldDescriptions[i], i, dst, err)
 			rows.fatal(err)
 			return err
 		}
@@ -282,22 +327,36 @@ func (rows *baseRows) Scan(dest ...any) error {
 }
 
 func (rows *baseRows) Values() ([]any, error) {
+	return rows.ValuesInto(make([]any, 0, len(rows.FieldDescriptions())))
+}
+
+// ValuesInto implements valuesIntoer.
+func (rows *baseRows) ValuesInto(dst []any) ([]any, error) {
 	if rows.closed {
 		return nil, errors.New("rows is closed")
 	}
 
-	values := make([]any, 0, len(rows.FieldDescriptions()))
+	fieldDescriptions := rows.FieldDescriptions()
+
+	if rows.valueTypes == nil {
+		rows.valueTypes = make([]*pgtype.Type, len(fieldDescriptions))
+		for i := range fieldDescriptions {
+			rows.valueTypes[i], _ = rows.typeMap.TypeForOID(fieldDescriptions[i].DataTypeOID)
+		}
+	}
 
-	for i := range rows.FieldDescriptions() {
+	values := dst[:0]
+
+	for i := range fieldDescriptions {
 		buf := rows.values[i]
-		fd := &rows.FieldDescriptions()[i]
+		fd := &fieldDescriptions[i]
 
 		if buf == nil {
 			values = append(values, nil)
 			continue
 		}
 
-		if dt, ok := rows.typeMap.TypeForOID(fd.DataTypeOID); ok {
+		if dt := rows.valueTypes[i]; dt != nil {
 			value, err := dt.Codec.DecodeValue(rows.typeMap, fd.DataTypeOID, fd.Format, buf)
 			if err != nil {
 				rows.fatal(err)
@@ -324,6 +383,27 @@ func (rows *baseRows) Values() ([]any, error) {
 	return values, rows.Err()
 }
 
+// valuesIntoer is implemented by Rows that can decode the row values into a slice provided by the caller.
+type valuesIntoer interface {
+	ValuesInto(dst []any) ([]any, error)
+}
+
+// ValuesInto returns the decoded values of the current row of rows like Rows.Values. The values are appended to
+// dst[:0] so that the same slice can be reused for every row instead of allocating a new one. The returned slice is
+// only valid until the next call with the same dst. If rows does not support ValuesInto, Values is called and its
+// result is copied into dst.
+func ValuesInto(rows Rows, dst []any) ([]any, error) {
+	if r, ok := rows.(valuesIntoer); ok {
+		return r.ValuesInto(dst)
+	}
+
+	values, err := rows.Values()
+	if err != nil {
+		return nil, err
+	}
+	return append(dst[:0], values...), nil
+}
+
 func (rows *baseRows) RawValues() [][]byte {
 	return rows.values
 }
@@ -332,19 +412,129 @@ func (rows *baseRows) Conn() *Conn {
 	return rows.conn
 }
 
+// ScanArgError is returned when a column cannot be scanned into its destination.
 type ScanArgError struct {
 	ColumnIndex int
-	Err         error
+
+	// FieldName is the name of the column. It is "?column?" when PostgreSQL could not determine a name.
+	FieldName string
+
+	// DataTypeOID is the OID of the PostgreSQL type of the column.
+	DataTypeOID uint32
+
+	// DestType is the type of the destination.
+	DestType reflect.Type
+
+	Err error
 }
 
 func (e ScanArgError) Error() string {
-	return fmt.Sprintf("can't scan into dest[%d]: %v", e.ColumnIndex, e.Err)
+	if e.FieldName == "" || e.FieldName == "?column?" { // Don't include the field name if it's unknown
+		return fmt.Sprintf("can't scan into dest[%d]: %v", e.ColumnIndex, e.Err)
+	}
+
+	return fmt.Sprintf("can't scan into dest[%d] (col: %s): %v", e.ColumnIndex, e.FieldName, e.Err)
+}
+
+func newScanArgError(fd *pgconn.FieldDescription, columnIndex int, dest any, err error) ScanArgError {
+	return ScanArgError{
+		ColumnIndex: columnIndex,
+		FieldName:   fd.Name,
+		DataTypeOID: fd.DataTypeOID,
+		DestType:    reflect.TypeOf(dest),
+		Err:         err,
+	}
 }
 
 func (e ScanArgError) Unwrap() error {
 	return e.Err
 }
 
+// NamedDest returns a destination for Rows.Scan that is bound to the column called name instead of by position. If
+// any destination passed to Scan is a NamedDest then all of them must be. Columns without a corresponding NamedDest
+// are skipped. Column names are matched exactly and it is an error if the row has more than one column called name.
+func NamedDest(name string, dest any) any {
+	return namedDest{name: name, dest: dest}
+}
+
+type namedDest struct {
+	name string
+	dest any
+}
+
+func hasNamedDest(dests []any) bool {
+	for _, d := range dests {
+		if _, ok := d.(namedDest); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// positionalDestsFromNamedDests returns a slice of destinations with one element per field with the destinations of
+// dests placed at the position of the field they are named for.
+func positionalDestsFromNamedDests(fieldDescriptions []pgconn.FieldDescription, dests []any) ([]any, error) {
+	positionalDests := make([]any, len(fieldDescriptions))
+	for _, d := range dests {
+		nd, ok := d.(namedDest)
+		if !ok {
+			return nil, fmt.Errorf("cannot mix named and positional destinations")
+		}
+
+		i := fieldPosByName(fieldDescriptions, nd.name, false)
+		if i == -1 {
+			return nil, fmt.Errorf("cannot find column %s for named destination", nd.name)
+		}
+		if fieldPosByName(fieldDescriptions[i+1:], nd.name, false) != -1 {
+			return nil, fmt.Errorf("duplicate column %s in row", nd.name)
+		}
+		if positionalDests[i] != nil {
+			return nil, fmt.Errorf("duplicate named destination for column %s", nd.name)
+		}
+		positionalDests[i] = nd.dest
+	}
+
+	return positionalDests, nil
+}
+
+// NamedDests is a set of scan destinations keyed by column name. When passed as the only destination to Rows.Scan or
+// Row.Scan the columns are scanned into the destinations by name regardless of their position. It is an error if the
+// row does not have a column for every destination or if it has more than one column with the name of a destination.
+// Columns without a destination are skipped.
+type NamedDests map[string]any
+
+// ScanRow implements the RowScanner interface.
+func (nd NamedDests) ScanRow(rows Rows) error {
+	dests := make([]any, 0, len(nd))
+	for name, d := range nd {
+		dests = append(dests, namedDest{name: name, dest: d})
+	}
+
+	dest, err := positionalDestsFromNamedDests(rows.FieldDescriptions(), dests)
+	if err != nil {
+		return err
+	}
+
+	return rows.Scan(dest...)
+}
+
+// PartialDests is a list of positional scan destinations for the leading columns of a row. When passed as the only
+// destination to Rows.Scan or Row.Scan the first len(PartialDests) columns are scanned and any remaining columns are
+// ignored. It is an error if the row has fewer columns than destinations.
+type PartialDests []any
+
+// ScanRow implements the RowScanner interface.
+func (pd PartialDests) ScanRow(rows Rows) error {
+	fieldDescriptions := rows.FieldDescriptions()
+	if len(pd) > len(fieldDescriptions) {
+		return fmt.Errorf("number of destinations must not exceed number of field descriptions, got %d and %d", len(pd), len(fieldDescriptions))
+	}
+
+	dest := make([]any, len(fieldDescriptions))
+	copy(dest, pd)
+	return rows.Scan(dest...)
+}
+
 // ScanRow decodes raw row data into dest. It can be used to scan rows read from the lower level pgconn interface.
 //
 // typeMap - OID to Go type mapping.
@@ -366,7 +556,7 @@ func ScanRow(typeMap *pgtype.Map, fieldDescriptions []pgconn.FieldDescription, v
 
 		err := typeMap.Scan(fieldDescriptions[i].DataTypeOID, fieldDescriptions[i].Format, values[i], d)
 		if err != nil {
-			return ScanArgError{ColumnIndex: i, Err: err}
+			return newScanArgError(&fieldDescriptions[i], i, d, err)
 		}
 	}
 
@@ -626,6 +816,10 @@ func computeStructFields(
 // RowToStructByName returns a T scanned from row. T must be a struct. T must have the same number of named public
 // fields as row has fields. The row and T fields will be matched by name. The match is case-insensitive. The database
 // column name can be overridden with a "db" struct tag. If the "db" struct tag is "-" then the field will be ignored.
+// A "db" struct tag on an embedded struct is used as a prefix for the column names of its fields joined with "_". e.g.
+// The fields ID and Name of an embedded struct tagged "author" are matched with the columns author_id and author_name.
+// A "db" struct tag of "-" on an embedded struct ignores all of its fields. Previous versions ignored "db" struct tags
+// on embedded structs. Remove the tag to match the fields of an embedded struct without a prefix.
 func RowToStructByName[T any](row CollectableRow) (T, error) {
 	var value T
 	err := (&namedStructRowScanner{ptrToStruct: &value}).ScanRow(row)
@@ -635,7 +829,7 @@ func RowToStructByName[T any](row CollectableRow) (T, error) {
 // RowToAddrOfStructByName returns the address of a T scanned from row. T must be a struct. T must have the same number
 // of named public fields as row has fields. The row and T fields will be matched by name. The match is
 // case-insensitive. The database column name can be overridden with a "db" struct tag. If the "db" struct tag is "-"
-// then the field will be ignored.
+// then the field will be ignored. Embedded structs are handled as in RowToStructByName.
 func RowToAddrOfStructByName[T any](row CollectableRow) (*T, error) {
 	var value T
 	err := (&namedStructRowScanner{ptrToStruct: &value}).ScanRow(row)
@@ -645,6 +839,7 @@ func RowToAddrOfStructByName[T any](row CollectableRow) (*T, error) {
 // RowToStructByNameLax returns a T scanned from row. T must be a struct. T must have greater than or equal number of named public
 // fields as row has fields. The row and T fields will be matched by name. The match is case-insensitive. The database
 // column name can be overridden with a "db" struct tag. If the "db" struct tag is "-" then the field will be ignored.
+// Embedded structs are handled as in RowToStructByName.
 func RowToStructByNameLax[T any](row CollectableRow) (T, error) {
 	var value T
 	err := (&namedStructRowScanner{ptrToStruct: &value, lax: true}).ScanRow(row)
@@ -654,7 +849,7 @@ func RowToStructByNameLax[T any](row CollectableRow) (T, error) {
 // RowToAddrOfStructByNameLax returns the address of a T scanned from row. T must be a struct. T must have greater than or
 // equal number of named public fields as row has fields. The row and T fields will be matched by name. The match is
 // case-insensitive. The database column name can be overridden with a "db" struct tag. If the "db" struct tag is "-"
-// then the field will be ignored.
+// then the field will be ignored. Embedded structs are handled as in RowToStructByName.
 func RowToAddrOfStructByNameLax[T any](row CollectableRow) (*T, error) {
 	var value T
 	err := (&namedStructRowScanner{ptrToStruct: &value, lax: true}).ScanRow(row)
@@ -711,13 +906,7 @@ func lookupNamedStructFields(
 	// We could probably do two-levels of caching, where we compute the key -> fields mapping
 	// for a type only once, cache it by type, then use that to compute the column -> fields
 	// mapping for a given set of columns.
-	fieldStack := make([]int, 0, 1)
-	fields, missingField := computeNamedStructFields(
-		fldDescs,
-		t,
-		make([]structRowField, len(fldDescs)),
-		&fieldStack,
-	)
+	fields, missingField := computeNamedStructFields(fldDescs, t)
 	for i, f := range fields {
 		if f.path == nil {
 			return nil, fmt.Errorf(
@@ -756,13 +945,35 @@ func joinFieldNames(fldDescs []pgconn.FieldDescription) string {
 	return b.String()
 }
 
-func computeNamedStructFields(
-	fldDescs []pgconn.FieldDescription,
-	t reflect.Type,
-	fields []structRowField,
-	fieldStack *[]int,
-) ([]structRowField, string) {
+func computeNamedStructFields(fldDescs []pgconn.FieldDescription, t reflect.Type) ([]structRowField, string) {
+	fields := make([]structRowField, len(fldDescs))
 	var missingField string
+	fieldStack := make([]int, 0, 1)
+	visitStructColumnFields(t, "", &fieldStack, func(prefix, name string, tagged bool, path []int) {
+		colName := prefix + name
+		fpos := fieldPosByName(fldDescs, colName, !tagged)
+		if fpos == -1 {
+			if missingField == "" {
+				missingField = colName
+			}
+			return
+		}
+		fields[fpos] = structRowField{
+			path: append([]int(nil), path...),
+		}
+	})
+
+	return fields, missingField
+}
+
+// visitStructColumnFields calls fn for every field of t that is mapped to a column by name. The fields of embedded
+// structs are visited as well. A "db" struct tag on an embedded struct is the prefix of the column names of its fields.
+// e.g. Fields of an embedded struct tagged "author" are mapped to columns named "author_id", "author_name", etc. A tag
+// of "-" skips a field or embedded struct.
+//
+// fn is passed the prefix from the tags of the embedded structs, the db tag of the field or its Go name if it is not
+// tagged, and the index path of the field. path is only valid during the call of fn.
+func visitStructColumnFields(t reflect.Type, prefix string, fieldStack *[]int, fn func(prefix, name string, tagged bool, path []int)) {
 	tail := len(*fieldStack)
 	*fieldStack = append(*fieldStack, 0)
 	for i := 0; i < t.NumField(); i++ {
@@ -772,50 +983,47 @@ func computeNamedStructFields(
 			// Field is unexported, skip it.
 			continue
 		}
+
+		dbTag, dbTagPresent := sf.Tag.Lookup(structTagKey)
+		if dbTagPresent {
+			dbTag, _, _ = strings.Cut(dbTag, ",")
+		}
+		if dbTag == "-" {
+			// Field is ignored, skip it.
+			continue
+		}
+
 		// Handle anonymous struct embedding, but do not try to handle embedded pointers.
 		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
-			var missingSubField string
-			fields, missingSubField = computeNamedStructFields(
-				fldDescs,
-				sf.Type,
-				fields,
-				fieldStack,
-			)
-			if missingField == "" {
-				missingField = missingSubField
+			subPrefix := prefix
+			if dbTag != "" {
+				subPrefix = prefix + dbTag + "_"
 			}
+			visitStructColumnFields(sf.Type, subPrefix, fieldStack, fn)
+			continue
+		}
+
+		if sf.PkgPath != "" {
+			// Embedded non-struct type that is unexported, skip it.
+			continue
+		}
+
+		if dbTagPresent {
+			fn(prefix, dbTag, true, *fieldStack)
 		} else {
-			dbTag, dbTagPresent := sf.Tag.Lookup(structTagKey)
-			if dbTagPresent {
-				dbTag, _, _ = strings.Cut(dbTag, ",")
-			}
-			if dbTag == "-" {
-				// Field is ignored, skip it.
-				continue
-			}
-			colName := dbTag
-			if !dbTagPresent {
-				colName = sf.Name
-			}
-			fpos := fieldPosByName(fldDescs, colName, !dbTagPresent)
-			if fpos == -1 {
-				if missingField == "" {
-					missingField = colName
-				}
-				continue
-			}
-			fields[fpos] = structRowField{
-				path: append([]int(nil), *fieldStack...),
-			}
+			fn(prefix, sf.Name, false, *fieldStack)
 		}
 	}
 	*fieldStack = (*fieldStack)[:tail]
-
-	return fields, missingField
 }
 
 const structTagKey = "db"
 
+func isPointerToStruct(v any) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct
+}
+
 func fieldPosByName(fldDescs []pgconn.FieldDescription, field string, normalize bool) (i int) {
 	i = -1
 
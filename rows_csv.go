@@ -0,0 +1,101 @@
+package pgx
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CSVOptions controls how WriteRowsCSV formats a result set.
+type CSVOptions struct {
+	// Delimiter is the field delimiter. If it is 0 then ',' is used.
+	Delimiter rune
+
+	// Null is written for NULL values. By default NULL is written as an empty field which is indistinguishable from an
+	// empty string.
+	Null string
+
+	// OmitHeader disables writing the column names as the first record.
+	OmitHeader bool
+}
+
+// WriteRowsCSV writes rows to w as CSV. Unless opts.OmitHeader is set the first record contains the column names. The
+// header is written even if the result set is empty. Columns received in the text format are written as received from
+// the server. Columns received in the binary format are decoded and then encoded
+// in the text format. Each row is written as soon as it is read so the result set is never buffered in memory.
+//
+// This function closes the rows automatically on return.
+func WriteRowsCSV(w io.Writer, rows Rows, opts CSVOptions) error {
+	defer rows.Close()
+
+	cw := csv.NewWriter(w)
+	if opts.Delimiter != 0 {
+		cw.Comma = opts.Delimiter
+	}
+
+	var typeMap *pgtype.Map
+	if conn := rows.Conn(); conn != nil {
+		typeMap = conn.TypeMap()
+	} else {
+		typeMap = pgtype.NewMap()
+	}
+
+	// The rows of a failed query have no field descriptions.
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	fieldDescriptions := rows.FieldDescriptions()
+	record := make([]string, len(fieldDescriptions))
+	if !opts.OmitHeader && len(fieldDescriptions) > 0 {
+		for i := range fieldDescriptions {
+			record[i] = fieldDescriptions[i].Name
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	var textBuf []byte
+	for rows.Next() {
+		var values []any
+		for i, raw := range rows.RawValues() {
+			fd := &fieldDescriptions[i]
+
+			switch {
+			case raw == nil:
+				record[i] = opts.Null
+			case fd.Format == TextFormatCode:
+				record[i] = string(raw)
+			default:
+				if values == nil {
+					var err error
+					values, err = rows.Values()
+					if err != nil {
+						return err
+					}
+				}
+
+				var err error
+				textBuf, err = typeMap.Encode(fd.DataTypeOID, TextFormatCode, values[i], textBuf[:0])
+				if err != nil {
+					return fmt.Errorf("cannot encode column %s as text: %w", fd.Name, err)
+				}
+				record[i] = string(textBuf)
+			}
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,45 @@
+package pgx_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxtest"
+)
+
+func TestWriteRowsCSV(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pgxtest.RunWithQueryExecModes(ctx, t, defaultConnTestRunner, nil, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		var buf bytes.Buffer
+		rows, _ := conn.Query(ctx, `select n as id, 'a,' || n as name, null::text as note, '2024-01-0' || n || ' 12:00:00'::text::timestamp as at from generate_series(1, 2) n`)
+		err := pgx.WriteRowsCSV(&buf, rows, pgx.CSVOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "id,name,note,at\n1,\"a,1\",,2024-01-01 12:00:00\n2,\"a,2\",,2024-01-02 12:00:00\n", buf.String())
+
+		buf.Reset()
+		rows, _ = conn.Query(ctx, `select n as id, null::text as note from generate_series(1, 2) n`)
+		err = pgx.WriteRowsCSV(&buf, rows, pgx.CSVOptions{Delimiter: ';', Null: `\N`, OmitHeader: true})
+		require.NoError(t, err)
+		require.Equal(t, "1;\\N\n2;\\N\n", buf.String())
+
+		buf.Reset()
+		rows, _ = conn.Query(ctx, `select n as id, 'a' as name from generate_series(1, 0) n`)
+		err = pgx.WriteRowsCSV(&buf, rows, pgx.CSVOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "id,name\n", buf.String())
+
+		buf.Reset()
+		rows, _ = conn.Query(ctx, `select 1/(3-n) from generate_series(1, 5) n`)
+		err = pgx.WriteRowsCSV(&buf, rows, pgx.CSVOptions{})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,72 @@
+package pgx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// EncodeRowsJSON writes rows to w as a JSON array with an object for each row. The object keys are the column names
+// and the values are the values returned by Rows.Values encoded with encoding/json. Each row is written to w as soon
+// as it is read so the result set is never buffered in memory.
+//
+// If an error occurs after some rows have been written then w will contain an incomplete JSON document.
+//
+// This function closes the rows automatically on return.
+func EncodeRowsJSON(w io.Writer, rows Rows) error {
+	defer rows.Close()
+
+	buf := []byte{'['}
+	var keys [][]byte
+
+	for rows.Next() {
+		if keys == nil {
+			fieldDescriptions := rows.FieldDescriptions()
+			keys = make([][]byte, len(fieldDescriptions))
+			for i := range fieldDescriptions {
+				key, err := json.Marshal(fieldDescriptions[i].Name)
+				if err != nil {
+					return err
+				}
+				keys[i] = key
+			}
+		} else {
+			buf = append(buf, ',')
+		}
+
+		values, err := rows.Values()
+		if err != nil {
+			return err
+		}
+
+		buf = append(buf, '{')
+		for i, v := range values {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, keys[i]...)
+			buf = append(buf, ':')
+
+			jsonValue, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("cannot encode column %s as JSON: %w", keys[i], err)
+			}
+			buf = append(buf, jsonValue...)
+		}
+		buf = append(buf, '}')
+
+		_, err = w.Write(buf)
+		if err != nil {
+			return err
+		}
+		buf = buf[:0]
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	buf = append(buf, ']')
+	_, err := w.Write(buf)
+	return err
+}
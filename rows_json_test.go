@@ -0,0 +1,43 @@
+package pgx_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxtest"
+)
+
+func TestEncodeRowsJSON(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pgxtest.RunWithQueryExecModes(ctx, t, defaultConnTestRunner, nil, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		var buf bytes.Buffer
+		rows, _ := conn.Query(ctx, `select n as id, 'name ' || n as name, null::text as note from generate_series(1, 3) n`)
+		err := pgx.EncodeRowsJSON(&buf, rows)
+		require.NoError(t, err)
+		require.JSONEq(t, `[
+			{"id": 1, "name": "name 1", "note": null},
+			{"id": 2, "name": "name 2", "note": null},
+			{"id": 3, "name": "name 3", "note": null}
+		]`, buf.String())
+
+		buf.Reset()
+		rows, _ = conn.Query(ctx, `select n from generate_series(1, 0) n`)
+		err = pgx.EncodeRowsJSON(&buf, rows)
+		require.NoError(t, err)
+		require.Equal(t, `[]`, buf.String())
+
+		buf.Reset()
+		rows, _ = conn.Query(ctx, `select 1/(3-n) from generate_series(1, 5) n`)
+		err = pgx.EncodeRowsJSON(&buf, rows)
+		require.Error(t, err)
+	})
+}
@@ -54,6 +54,94 @@ func TestRowScannerErrorIsFatalToRows(t *testing.T) {
 	})
 }
 
+func TestRowsScanNamedDest(t *testing.T) {
+	t.Parallel()
+
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		var name string
+		var age int32
+		err := conn.QueryRow(ctx, "select 'Adam' as name, 72 as age, true as ignored").Scan(
+			pgx.NamedDest("age", &age),
+			pgx.NamedDest("name", &name),
+		)
+		require.NoError(t, err)
+		require.Equal(t, "Adam", name)
+		require.Equal(t, int32(72), age)
+
+		err = conn.QueryRow(ctx, "select 'Adam' as name").Scan(pgx.NamedDest("missing", &name))
+		require.EqualError(t, err, "cannot find column missing for named destination")
+
+		err = conn.QueryRow(ctx, "select 'Adam' as name, 72 as age").Scan(pgx.NamedDest("name", &name), &age)
+		require.EqualError(t, err, "cannot mix named and positional destinations")
+
+		err = conn.QueryRow(ctx, "select 'Adam' as name, 72 as age").Scan(&name, pgx.NamedDest("age", &age))
+		require.EqualError(t, err, "cannot mix named and positional destinations")
+
+		err = conn.QueryRow(ctx, "select 'Adam' as name, 'Eve' as name").Scan(pgx.NamedDest("name", &name))
+		require.EqualError(t, err, "duplicate column name in row")
+	})
+}
+
+func TestRowsScanNamedDests(t *testing.T) {
+	t.Parallel()
+
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		var name string
+		var age int32
+		err := conn.QueryRow(ctx, "select true as ignored, 72 as age, 'Adam' as name").Scan(pgx.NamedDests{
+			"name": &name,
+			"age":  &age,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Adam", name)
+		require.Equal(t, int32(72), age)
+
+		err = conn.QueryRow(ctx, "select 'Adam' as name").Scan(pgx.NamedDests{"name": &name, "age": &age})
+		require.EqualError(t, err, "cannot find column age for named destination")
+
+		err = conn.QueryRow(ctx, "select 'Adam' as name, 'Eve' as name").Scan(pgx.NamedDests{"name": &name})
+		require.EqualError(t, err, "duplicate column name in row")
+	})
+}
+
+func TestRowsScanPartialDests(t *testing.T) {
+	t.Parallel()
+
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		var name string
+		var age int32
+		err := conn.QueryRow(ctx, "select 'Adam' as name, 72 as age, now() as debug").Scan(pgx.PartialDests{&name, &age})
+		require.NoError(t, err)
+		require.Equal(t, "Adam", name)
+		require.Equal(t, int32(72), age)
+
+		err = conn.QueryRow(ctx, "select 'Adam' as name").Scan(pgx.PartialDests{&name, &age})
+		require.EqualError(t, err, "number of destinations must not exceed number of field descriptions, got 2 and 1")
+	})
+}
+
+func TestValuesInto(t *testing.T) {
+	t.Parallel()
+
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		rows, err := conn.Query(ctx, "select n, 'row ' || n from generate_series(1, 3) n")
+		require.NoError(t, err)
+		defer rows.Close()
+
+		buf := make([]any, 0, 2)
+		var n int32
+		for rows.Next() {
+			n++
+			values, err := pgx.ValuesInto(rows, buf)
+			require.NoError(t, err)
+			require.Equal(t, []any{n, fmt.Sprintf("row %d", n)}, values)
+			require.Same(t, &buf[:1][0], &values[0])
+		}
+		require.NoError(t, rows.Err())
+		require.EqualValues(t, 3, n)
+	})
+}
+
 func TestForEachRow(t *testing.T) {
 	t.Parallel()
 
@@ -738,6 +826,71 @@ func TestRowToStructByNameEmbeddedStruct(t *testing.T) {
 	})
 }
 
+func TestRowsScanStructByName(t *testing.T) {
+	type person struct {
+		Last  string `db:"last_name"`
+		First string `db:"first_name"`
+		Age   int32
+	}
+
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		var p person
+		err := conn.QueryRow(ctx, `select 42 as age, 'John' as first_name, 'Smith' as last_name`).Scan(&p)
+		require.NoError(t, err)
+		assert.Equal(t, person{Last: "Smith", First: "John", Age: 42}, p)
+
+		rows, _ := conn.Query(ctx, `select 'John' as first_name, 'Smith' as last_name, n as age from generate_series(0, 9) n`)
+		var i int32
+		for rows.Next() {
+			err = rows.Scan(&p)
+			require.NoError(t, err)
+			assert.Equal(t, person{Last: "Smith", First: "John", Age: i}, p)
+			i++
+		}
+		require.NoError(t, rows.Err())
+		assert.EqualValues(t, 10, i)
+
+		// check missing fields in a returned row
+		err = conn.QueryRow(ctx, `select 'Smith' as last_name, 42 as age`).Scan(&p)
+		assert.ErrorContains(t, err, "cannot find field first_name in returned row")
+
+		err = conn.QueryRow(ctx, `select 42 as age, 'John' as first_name, 'Smith' as last_name`).Scan((*person)(nil))
+		assert.EqualError(t, err, "cannot scan into nil *pgx_test.person")
+	})
+}
+
+func TestRowToStructByNameEmbeddedStructWithPrefix(t *testing.T) {
+	type Author struct {
+		ID   int32 `db:"id"`
+		Name string
+	}
+
+	type book struct {
+		ID     int32
+		Title  string
+		Author `db:"author"`
+	}
+
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		rows, _ := conn.Query(ctx, `select n as id, 'Dune' as title, 7 as author_id, 'Frank Herbert' as author_name from generate_series(0, 9) n`)
+		slice, err := pgx.CollectRows(rows, pgx.RowToStructByName[book])
+		assert.NoError(t, err)
+
+		assert.Len(t, slice, 10)
+		for i := range slice {
+			assert.EqualValues(t, i, slice[i].ID)
+			assert.Equal(t, "Dune", slice[i].Title)
+			assert.EqualValues(t, 7, slice[i].Author.ID)
+			assert.Equal(t, "Frank Herbert", slice[i].Author.Name)
+		}
+
+		// check missing fields in a returned row
+		rows, _ = conn.Query(ctx, `select n as id, 'Dune' as title, 'Frank Herbert' as author_name from generate_series(0, 9) n`)
+		_, err = pgx.CollectRows(rows, pgx.RowToStructByName[book])
+		assert.ErrorContains(t, err, "cannot find field author_id in returned row")
+	})
+}
+
 func ExampleRowToStructByName() {
 	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
 	defer cancel()
@@ -3,9 +3,13 @@ package pgx
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/rand"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/internal/sanitize"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
@@ -44,8 +48,15 @@ type TxOptions struct {
 	AccessMode     TxAccessMode
 	DeferrableMode TxDeferrableMode
 
+	// StatementTimeout, LockTimeout, and IdleInTransactionSessionTimeout set the statement_timeout, lock_timeout, and
+	// idle_in_transaction_session_timeout settings with SET LOCAL right after the transaction begins. They only apply
+	// to the transaction. They are rounded up to whole milliseconds. A value of 0 leaves the setting unchanged.
+	StatementTimeout                time.Duration
+	LockTimeout                     time.Duration
+	IdleInTransactionSessionTimeout time.Duration
+
 	// BeginQuery is the SQL query that will be executed to begin the transaction. This allows using non-standard syntax
-	// such as BEGIN PRIORITY HIGH with CockroachDB. If set this will override the other settings.
+	// such as BEGIN PRIORITY HIGH with CockroachDB. If set this will override IsoLevel, AccessMode, and DeferrableMode.
 	BeginQuery string
 	// CommitQuery is the SQL query that will be executed to commit the transaction.
 	CommitQuery string
@@ -58,28 +69,46 @@ func (txOptions TxOptions) beginSQL() string {
 		return "begin"
 	}
 
+	var buf strings.Builder
+	buf.Grow(64) // 64 - maximum length of begin statement with available modes
+
 	if txOptions.BeginQuery != "" {
-		return txOptions.BeginQuery
+		buf.WriteString(txOptions.BeginQuery)
+	} else {
+		buf.WriteString("begin")
+
+		if txOptions.IsoLevel != "" {
+			buf.WriteString(" isolation level ")
+			buf.WriteString(string(txOptions.IsoLevel))
+		}
+		if txOptions.AccessMode != "" {
+			buf.WriteByte(' ')
+			buf.WriteString(string(txOptions.AccessMode))
+		}
+		if txOptions.DeferrableMode != "" {
+			buf.WriteByte(' ')
+			buf.WriteString(string(txOptions.DeferrableMode))
+		}
 	}
 
-	var buf strings.Builder
-	buf.Grow(64) // 64 - maximum length of string with available options
-	buf.WriteString("begin")
+	appendSetLocalTimeout(&buf, "statement_timeout", txOptions.StatementTimeout)
+	appendSetLocalTimeout(&buf, "lock_timeout", txOptions.LockTimeout)
+	appendSetLocalTimeout(&buf, "idle_in_transaction_session_timeout", txOptions.IdleInTransactionSessionTimeout)
 
-	if txOptions.IsoLevel != "" {
-		buf.WriteString(" isolation level ")
-		buf.WriteString(string(txOptions.IsoLevel))
-	}
-	if txOptions.AccessMode != "" {
-		buf.WriteByte(' ')
-		buf.WriteString(string(txOptions.AccessMode))
-	}
-	if txOptions.DeferrableMode != "" {
-		buf.WriteByte(' ')
-		buf.WriteString(string(txOptions.DeferrableMode))
+	return buf.String()
+}
+
+// appendSetLocalTimeout appends a SET LOCAL statement for the timeout setting name to buf if d is greater than 0.
+func appendSetLocalTimeout(buf *strings.Builder, name string, d time.Duration) {
+	if d <= 0 {
+		return
 	}
 
-	return buf.String()
+	ms := (d + time.Millisecond - 1) / time.Millisecond
+	buf.WriteString("; set local ")
+	buf.WriteString(name)
+	buf.WriteString(" = ")
+	buf.WriteString(strconv.FormatInt(int64(ms), 10))
 }
 
 var ErrTxClosed = errors.New("tx is closed")
@@ -89,6 +118,10 @@ var ErrTxClosed = errors.New("tx is closed")
 // it is treated as ROLLBACK.
 var ErrTxCommitRollback = errors.New("commit unexpectedly resulted in rollback")
 
+// ErrTxCommitOutcomeUnknown occurs when Commit fails in a way that does not tell whether the transaction was committed,
+// e.g. the connection was lost after COMMIT was sent. Any other Commit error means the transaction was not committed.
+var ErrTxCommitOutcomeUnknown = errors.New("commit outcome unknown")
+
 // Begin starts a transaction. Unlike database/sql, the context only affects the begin command. i.e. there is no
 // auto-rollback on context cancellation.
 func (c *Conn) Begin(ctx context.Context) (Tx, error) {
@@ -98,6 +131,16 @@ func (c *Conn) Begin(ctx context.Context) (Tx, error) {
 // BeginTx starts a transaction with txOptions determining the transaction mode. Unlike database/sql, the context only
 // affects the begin command. i.e. there is no auto-rollback on context cancellation.
 func (c *Conn) BeginTx(ctx context.Context, txOptions TxOptions) (Tx, error) {
+	if c.config.ReadOnly {
+		// Check before sending anything. A failed begin closes the connection.
+		if txOptions.AccessMode == ReadWrite {
+			return nil, fmt.Errorf("%w: cannot begin read write transaction", ErrReadOnly)
+		}
+		if txOptions.BeginQuery != "" {
+			return nil, fmt.Errorf("%w: cannot begin transaction with BeginQuery", ErrReadOnly)
+		}
+	}
+
 	_, err := c.Exec(ctx, txOptions.beginSQL())
 	if err != nil {
 		// begin should never fail unless there is an underlying connection issue or
@@ -106,10 +149,76 @@ func (c *Conn) BeginTx(ctx context.Context, txOptions TxOptions) (Tx, error) {
 		return nil, err
 	}
 
-	return &dbTx{
+	tx := &dbTx{
 		conn:        c,
 		commitQuery: txOptions.CommitQuery,
-	}, nil
+	}
+	if h := c.config.TxHooks; h.BeforeCommit != nil || h.AfterCommit != nil || h.AfterRollback != nil {
+		tx.hooks = []TxHooks{h}
+	}
+	c.tx = tx
+
+	return tx, nil
+}
+
+// PrepareTransaction prepares the real transaction of tx for two-phase commit with PREPARE TRANSACTION using the global
+// transaction identifier gid. The transaction is then closed. The prepared transaction must be finished with
+// Conn.CommitPrepared or Conn.RollbackPrepared, possibly on another connection. A pseudo nested transaction cannot be
+// prepared. ErrTxClosed is returned if tx is closed.
+func PrepareTransaction(ctx context.Context, tx Tx, gid string) error {
+	realTx, nested, err := findDBTx(tx)
+	if err != nil {
+		return err
+	}
+	if nested {
+		return errors.New("cannot prepare a pseudo nested transaction")
+	}
+
+	return realTx.prepareTransaction(ctx, gid)
+}
+
+// txUnwrapper is implemented by a Tx that wraps another Tx such as pgxpool.Tx.
+type txUnwrapper interface {
+	UnwrapTx() Tx
+}
+
+// findDBTx returns the real transaction behind tx. Wrappers that implement UnwrapTx are unwrapped and a pseudo nested
+// transaction is resolved to the real transaction it belongs to, in which case nested is true. ErrTxClosed is returned
+// if tx or a transaction it belongs to is closed.
+func findDBTx(tx Tx) (realTx *dbTx, nested bool, err error) {
+	for {
+		switch t := tx.(type) {
+		case *dbTx:
+			if t.closed {
+				return nil, nested, ErrTxClosed
+			}
+			return t, nested, nil
+		case *dbSimulatedNestedTx:
+			if t.closed {
+				return nil, true, ErrTxClosed
+			}
+			nested = true
+			tx = t.tx
+		case txUnwrapper:
+			tx = t.UnwrapTx()
+		default:
+			return nil, nested, fmt.Errorf("cannot find the pgx transaction of %T", tx)
+		}
+	}
+}
+
+// CommitPrepared commits the transaction prepared for two-phase commit with the global transaction identifier gid. See
+// PrepareTransaction.
+func (c *Conn) CommitPrepared(ctx context.Context, gid string) error {
+	_, err := c.Exec(ctx, "commit prepared "+sanitize.QuoteString(gid))
+	return err
+}
+
+// RollbackPrepared rolls back the transaction prepared for two-phase commit with the global transaction identifier gid.
+// See PrepareTransaction.
+func (c *Conn) RollbackPrepared(ctx context.Context, gid string) error {
+	_, err := c.Exec(ctx, "rollback prepared "+sanitize.QuoteString(gid))
+	return err
 }
 
 // Tx represents a database transaction.
@@ -126,7 +235,9 @@ type Tx interface {
 	// Commit commits the transaction if this is a real transaction or releases the savepoint if this is a pseudo nested
 	// transaction. Commit will return an error where errors.Is(ErrTxClosed) is true if the Tx is already closed, but is
 	// otherwise safe to call multiple times. If the commit fails with a rollback status (e.g. the transaction was already
-	// in a broken state) then an error where errors.Is(ErrTxCommitRollback) is true will be returned.
+	// in a broken state) then an error where errors.Is(ErrTxCommitRollback) is true will be returned. If it is unknown
+	// whether a real transaction was committed (e.g. the connection was lost) then an error where
+	// errors.Is(ErrTxCommitOutcomeUnknown) is true will be returned.
 	Commit(ctx context.Context) error
 
 	// Rollback rolls back the transaction if this is a real transaction or rolls back to the savepoint if this is a
@@ -159,6 +270,7 @@ type dbTx struct {
 	savepointNum int64
 	closed       bool
 	commitQuery  string
+	hooks        []TxHooks
 }
 
 // Begin starts a pseudo nested transaction implemented with a savepoint.
@@ -182,6 +294,22 @@ func (tx *dbTx) Commit(ctx context.Context) error {
 		return ErrTxClosed
 	}
 
+	if ctx.Err() != nil && tx.conn.config.RollbackTimeout > 0 {
+		if err := tx.Rollback(ctx); err != nil {
+			return err
+		}
+		return ctx.Err()
+	}
+
+	for _, h := range tx.hooks {
+		if h.BeforeCommit != nil {
+			if err := h.BeforeCommit(ctx, tx); err != nil {
+				_ = tx.Rollback(ctx) // already have error to return
+				return err
+			}
+		}
+	}
+
 	commandSQL := "commit"
 	if tx.commitQuery != "" {
 		commandSQL = tx.commitQuery
@@ -193,12 +321,23 @@ func (tx *dbTx) Commit(ctx context.Context) error {
 		if tx.conn.PgConn().TxStatus() != 'I' {
 			_ = tx.conn.Close(ctx) // already have error to return
 		}
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) && !pgconn.SafeToRetry(err) {
+			return fmt.Errorf("%w: %w", ErrTxCommitOutcomeUnknown, err)
+		}
 		return err
 	}
 	if commandTag.String() == "ROLLBACK" {
+		tx.afterRollback(ctx)
 		return ErrTxCommitRollback
 	}
 
+	for _, h := range tx.hooks {
+		if h.AfterCommit != nil {
+			h.AfterCommit(ctx, tx.conn)
+		}
+	}
+
 	return nil
 }
 
@@ -211,14 +350,48 @@ func (tx *dbTx) Rollback(ctx context.Context) error {
 		return ErrTxClosed
 	}
 
-	_, err := tx.conn.Exec(ctx, "rollback")
+	rollbackCtx := ctx
+	if timeout := tx.conn.config.RollbackTimeout; timeout > 0 && ctx.Err() != nil {
+		var cancel context.CancelFunc
+		rollbackCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer cancel()
+	}
+
+	_, err := tx.conn.Exec(rollbackCtx, "rollback")
 	tx.closed = true
 	if err != nil {
 		// A rollback failure leaves the connection in an undefined state
 		tx.conn.die()
+		tx.afterRollback(ctx)
 		return err
 	}
 
+	tx.afterRollback(ctx)
+	return nil
+}
+
+func (tx *dbTx) afterRollback(ctx context.Context) {
+	for _, h := range tx.hooks {
+		if h.AfterRollback != nil {
+			h.AfterRollback(ctx, tx.conn)
+		}
+	}
+}
+
+// prepareTransaction prepares the transaction for two-phase commit.
+func (tx *dbTx) prepareTransaction(ctx context.Context, gid string) error {
+	commandTag, err := tx.conn.Exec(ctx, "prepare transaction "+sanitize.QuoteString(gid))
+	tx.closed = true
+	if err != nil {
+		if tx.conn.PgConn().TxStatus() != 'I' {
+			_ = tx.conn.Close(ctx) // already have error to return
+		}
+		return err
+	}
+	if commandTag.String() == "ROLLBACK" {
+		return ErrTxCommitRollback
+	}
+
 	return nil
 }
 
@@ -440,3 +613,152 @@ func beginFuncExec(ctx context.Context, tx Tx, fn func(Tx) error) (err error) {
 
 	return tx.Commit(ctx)
 }
+
+// TxRetryPolicy controls how ExecuteTxWithRetry retries a transaction. A zero field uses its default.
+type TxRetryPolicy struct {
+	// MaxAttempts is the maximum number of times the transaction is run, including the first attempt. The default is 5.
+	MaxAttempts int
+
+	// InitialBackoff is the maximum delay before the first retry. The maximum delay doubles for each later retry. The
+	// actual delay is chosen at random up to the maximum. The default is 10ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff limits the maximum delay. The default is 1s.
+	MaxBackoff time.Duration
+}
+
+// ExecuteTxWithRetry calls BeginTxFunc with db, txOptions, and fn. If the transaction fails with a serialization failure
+// (SQLSTATE 40001) or a deadlock (SQLSTATE 40P01) it is rolled back and run again after a randomized exponential
+// backoff, up to retryPolicy.MaxAttempts times. fn must be safe to call more than once. Any other error is returned
+// immediately. If every attempt fails the error of the last attempt is returned.
+//
+// ctx is checked before each retry. If it is done, its error is returned wrapping the last transaction error.
+func ExecuteTxWithRetry(
+	ctx context.Context,
+	db interface {
+		BeginTx(ctx context.Context, txOptions TxOptions) (Tx, error)
+	},
+	txOptions TxOptions,
+	fn func(Tx) error,
+	retryPolicy TxRetryPolicy,
+) error {
+	maxAttempts := retryPolicy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	backoff := retryPolicy.InitialBackoff
+	if backoff <= 0 {
+		backoff = 10 * time.Millisecond
+	}
+	maxBackoff := retryPolicy.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = time.Second
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := BeginTxFunc(ctx, db, txOptions, fn)
+		if err == nil || attempt >= maxAttempts || !isRetryableTxError(err) {
+			return err
+		}
+
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(backoff) + 1)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// isRetryableTxError returns true if err is a serialization failure or a deadlock.
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
+// TxHooks are functions called during the lifecycle of a real transaction. Pseudo nested transactions do not call
+// hooks. Any field may be nil.
+type TxHooks struct {
+	// BeforeCommit is called by Commit before the transaction is committed. If it returns an error the transaction is
+	// rolled back and Commit returns the error.
+	BeforeCommit func(ctx context.Context, tx Tx) error
+
+	// AfterCommit is called after the transaction is committed.
+	AfterCommit func(ctx context.Context, conn *Conn)
+
+	// AfterRollback is called after the transaction is rolled back. This includes a Commit that resulted in a rollback
+	// and a failed rollback that closed the connection. It is not called if the outcome of Commit is unknown because of
+	// a connection failure.
+	AfterRollback func(ctx context.Context, conn *Conn)
+}
+
+// AddTxHooks adds hooks to the real transaction that tx belongs to. If tx is a pseudo nested transaction, the hooks are
+// called for the outermost transaction. This allows, for example, publishing messages only after the data they refer
+// to is committed. A prepared transaction does not call hooks. ErrTxClosed is returned if tx is closed.
+func AddTxHooks(tx Tx, hooks TxHooks) error {
+	realTx, _, err := findDBTx(tx)
+	if err != nil {
+		return err
+	}
+
+	realTx.hooks = append(realTx.hooks, hooks)
+	return nil
+}
+
+// SetLocal sets the run-time parameter name to value for the rest of the transaction tx with set_config. Both are sent
+// as query arguments so neither needs to be quoted. If the connection is read-only, settings that control the
+// transaction access mode are rejected with ErrReadOnly.
+func SetLocal(ctx context.Context, tx Tx, name, value string) error {
+	if conn := tx.Conn(); conn != nil {
+		if err := conn.checkReadOnlySetting(name); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(ctx, "select set_config($1, $2, true)", name, value)
+	return err
+}
+
+// ConstraintsMode is the mode set by SET CONSTRAINTS (deferred or immediate).
+type ConstraintsMode string
+
+// Constraints modes
+const (
+	ConstraintsDeferred  ConstraintsMode = "deferred"
+	ConstraintsImmediate ConstraintsMode = "immediate"
+)
+
+// SetConstraints sets the checking mode of the deferrable constraints names for the rest of the transaction tx with
+// SET CONSTRAINTS. If no names are given all deferrable constraints are set.
+func SetConstraints(ctx context.Context, tx Tx, mode ConstraintsMode, names ...Identifier) error {
+	switch mode {
+	case ConstraintsDeferred, ConstraintsImmediate:
+	default:
+		return fmt.Errorf("unknown ConstraintsMode: %s", mode)
+	}
+
+	var buf strings.Builder
+	buf.WriteString("set constraints ")
+	if len(names) == 0 {
+		buf.WriteString("all")
+	}
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(name.Sanitize())
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(string(mode))
+
+	_, err := tx.Exec(ctx, buf.String())
+	return err
+}
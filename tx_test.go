@@ -392,6 +392,52 @@ func TestBeginTxBeginQuery(t *testing.T) {
 	})
 }
 
+func TestBeginTxTimeouts(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	pgxtest.RunWithQueryExecModes(ctx, t, defaultConnTestRunner, nil, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		pgxtest.SkipCockroachDB(t, conn, "Server does not support SET LOCAL lock_timeout")
+
+		var defaultStatementTimeout string
+		err := conn.QueryRow(ctx, "select current_setting('statement_timeout')").Scan(&defaultStatementTimeout)
+		require.NoError(t, err)
+
+		tx, err := conn.BeginTx(ctx, pgx.TxOptions{
+			IsoLevel:                        pgx.RepeatableRead,
+			StatementTimeout:                1500 * time.Millisecond,
+			LockTimeout:                     2 * time.Second,
+			IdleInTransactionSessionTimeout: time.Minute,
+		})
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+
+		var statementTimeout, lockTimeout, idleTimeout, isoLevel string
+		err = tx.QueryRow(ctx, `select current_setting('statement_timeout'), current_setting('lock_timeout'),
+			current_setting('idle_in_transaction_session_timeout'), current_setting('transaction_isolation')`,
+		).Scan(&statementTimeout, &lockTimeout, &idleTimeout, &isoLevel)
+		require.NoError(t, err)
+		require.Equal(t, "1500ms", statementTimeout)
+		require.Equal(t, "2s", lockTimeout)
+		require.Equal(t, "1min", idleTimeout)
+		require.Equal(t, "repeatable read", isoLevel)
+
+		_, err = tx.Exec(ctx, "select pg_sleep(2)")
+		var pgErr *pgconn.PgError
+		require.ErrorAs(t, err, &pgErr)
+		require.Equal(t, "57014", pgErr.Code)
+
+		err = tx.Rollback(ctx)
+		require.NoError(t, err)
+
+		err = conn.QueryRow(ctx, "select current_setting('statement_timeout')").Scan(&statementTimeout)
+		require.NoError(t, err)
+		require.Equal(t, defaultStatementTimeout, statementTimeout)
+	})
+}
+
 func TestTxNestedTransactionCommit(t *testing.T) {
 	t.Parallel()
 
@@ -642,3 +688,296 @@ func TestTxSendBatchClosed(t *testing.T) {
 	_, err = br.Query()
 	require.Error(t, err)
 }
+
+func TestExecuteTxWithRetry(t *testing.T) {
+	t.Parallel()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	mustExec(t, conn, "create temporary table foo(id integer)")
+
+	retryPolicy := pgx.TxRetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	attempts := 0
+	err := pgx.ExecuteTxWithRetry(context.Background(), conn, pgx.TxOptions{IsoLevel: pgx.Serializable}, func(tx pgx.Tx) error {
+		attempts++
+		_, err := tx.Exec(context.Background(), "insert into foo(id) values ($1)", attempts)
+		require.NoError(t, err)
+		if attempts < 3 {
+			return &pgconn.PgError{Code: "40001", Message: "could not serialize access"}
+		}
+		return nil
+	}, retryPolicy)
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+
+	rows, _ := conn.Query(context.Background(), "select id from foo")
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int32])
+	require.NoError(t, err)
+	require.Equal(t, []int32{3}, ids)
+
+	attempts = 0
+	err = pgx.ExecuteTxWithRetry(context.Background(), conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		attempts++
+		return &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}
+	}, retryPolicy)
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	require.Equal(t, "40P01", pgErr.Code)
+	require.Equal(t, 3, attempts)
+
+	attempts = 0
+	err = pgx.ExecuteTxWithRetry(context.Background(), conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		attempts++
+		return errors.New("some error")
+	}, retryPolicy)
+	require.EqualError(t, err, "some error")
+	require.Equal(t, 1, attempts)
+
+	ensureConnValid(t, conn)
+}
+
+func TestTxPrepareTransaction(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	var maxPreparedTransactions int
+	err := conn.QueryRow(ctx, "select current_setting('max_prepared_transactions')::int").Scan(&maxPreparedTransactions)
+	require.NoError(t, err)
+	if maxPreparedTransactions == 0 {
+		t.Skip("prepared transactions are disabled")
+	}
+
+	mustExec(t, conn, "drop table if exists pgx_prepare_transaction_test")
+	mustExec(t, conn, "create table pgx_prepare_transaction_test(id integer)")
+	defer mustExec(t, conn, "drop table pgx_prepare_transaction_test")
+
+	gid := "pgx test's gid"
+
+	tx, err := conn.Begin(ctx)
+	require.NoError(t, err)
+	_, err = tx.Exec(ctx, "insert into pgx_prepare_transaction_test(id) values (1)")
+	require.NoError(t, err)
+
+	nestedTx, err := tx.Begin(ctx)
+	require.NoError(t, err)
+	require.Error(t, pgx.PrepareTransaction(ctx, nestedTx, gid))
+	require.NoError(t, nestedTx.Commit(ctx))
+
+	err = pgx.PrepareTransaction(ctx, tx, gid)
+	require.NoError(t, err)
+	require.ErrorIs(t, tx.Commit(ctx), pgx.ErrTxClosed)
+	require.EqualValues(t, 'I', conn.PgConn().TxStatus())
+
+	// A closed Tx does not prepare the transaction the connection started since.
+	otherTx, err := conn.Begin(ctx)
+	require.NoError(t, err)
+	require.ErrorIs(t, pgx.PrepareTransaction(ctx, tx, gid+" 2"), pgx.ErrTxClosed)
+	require.EqualValues(t, 'T', conn.PgConn().TxStatus())
+	require.NoError(t, otherTx.Rollback(ctx))
+
+	otherConn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, otherConn)
+
+	var n int64
+	err = otherConn.QueryRow(ctx, "select count(*) from pgx_prepare_transaction_test").Scan(&n)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, n)
+
+	err = otherConn.CommitPrepared(ctx, gid)
+	require.NoError(t, err)
+
+	err = conn.QueryRow(ctx, "select count(*) from pgx_prepare_transaction_test").Scan(&n)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+
+	err = conn.RollbackPrepared(ctx, gid)
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	require.Equal(t, "42704", pgErr.Code)
+
+	ensureConnValid(t, conn)
+}
+
+func TestTxHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	var calls []string
+	config := mustParseConfig(t, os.Getenv("PGX_TEST_DATABASE"))
+	config.TxHooks = pgx.TxHooks{
+		AfterCommit: func(ctx context.Context, conn *pgx.Conn) {
+			calls = append(calls, "conn after commit")
+		},
+		AfterRollback: func(ctx context.Context, conn *pgx.Conn) {
+			calls = append(calls, "conn after rollback")
+		},
+	}
+
+	conn := mustConnect(t, config)
+	defer closeConn(t, conn)
+
+	txHooks := pgx.TxHooks{
+		BeforeCommit: func(ctx context.Context, tx pgx.Tx) error {
+			calls = append(calls, "before commit")
+			return nil
+		},
+		AfterCommit: func(ctx context.Context, conn *pgx.Conn) {
+			calls = append(calls, "after commit")
+		},
+		AfterRollback: func(ctx context.Context, conn *pgx.Conn) {
+			calls = append(calls, "after rollback")
+		},
+	}
+
+	tx, err := conn.Begin(ctx)
+	require.NoError(t, err)
+	nestedTx, err := tx.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, pgx.AddTxHooks(nestedTx, txHooks))
+	require.NoError(t, nestedTx.Commit(ctx))
+	require.Empty(t, calls)
+	require.NoError(t, tx.Commit(ctx))
+	require.Equal(t, []string{"before commit", "conn after commit", "after commit"}, calls)
+	require.ErrorIs(t, pgx.AddTxHooks(tx, txHooks), pgx.ErrTxClosed)
+	require.ErrorIs(t, pgx.AddTxHooks(nestedTx, txHooks), pgx.ErrTxClosed)
+
+	calls = nil
+	closedTx := tx
+	tx, err = conn.Begin(ctx)
+	require.NoError(t, err)
+	// Hooks added with a closed Tx are not added to the transaction the connection started since.
+	require.ErrorIs(t, pgx.AddTxHooks(closedTx, txHooks), pgx.ErrTxClosed)
+	require.NoError(t, pgx.AddTxHooks(tx, txHooks))
+	require.NoError(t, tx.Rollback(ctx))
+	require.Equal(t, []string{"conn after rollback", "after rollback"}, calls)
+
+	calls = nil
+	tx, err = conn.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, pgx.AddTxHooks(tx, pgx.TxHooks{
+		BeforeCommit: func(ctx context.Context, tx pgx.Tx) error {
+			return errors.New("before commit failed")
+		},
+	}))
+	require.EqualError(t, tx.Commit(ctx), "before commit failed")
+	require.Equal(t, []string{"conn after rollback"}, calls)
+	require.EqualValues(t, 'I', conn.PgConn().TxStatus())
+
+	ensureConnValid(t, conn)
+}
+
+func TestSetLocal(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	tx, err := conn.Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+
+	err = pgx.SetLocal(ctx, tx, "application_name", "pgx's test")
+	require.NoError(t, err)
+
+	var applicationName string
+	err = tx.QueryRow(ctx, "select current_setting('application_name')").Scan(&applicationName)
+	require.NoError(t, err)
+	require.Equal(t, "pgx's test", applicationName)
+
+	require.NoError(t, tx.Rollback(ctx))
+
+	err = conn.QueryRow(ctx, "select current_setting('application_name')").Scan(&applicationName)
+	require.NoError(t, err)
+	require.NotEqual(t, "pgx's test", applicationName)
+
+	ensureConnValid(t, conn)
+}
+
+func TestSetConstraints(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	conn := mustConnectString(t, os.Getenv("PGX_TEST_DATABASE"))
+	defer closeConn(t, conn)
+
+	pgxtest.SkipCockroachDB(t, conn, "Server does not support deferred constraint (https://github.com/cockroachdb/cockroach/issues/31632)")
+
+	mustExec(t, conn, `create temporary table foo(
+		id integer,
+		constraint "foo id" unique (id) deferrable initially immediate
+	)`)
+
+	tx, err := conn.Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+
+	err = pgx.SetConstraints(ctx, tx, pgx.ConstraintsDeferred, pgx.Identifier{"foo id"})
+	require.NoError(t, err)
+
+	_, err = tx.Exec(ctx, "insert into foo(id) values (1), (1)")
+	require.NoError(t, err)
+
+	err = pgx.SetConstraints(ctx, tx, pgx.ConstraintsImmediate)
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	require.Equal(t, "23505", pgErr.Code)
+
+	require.NoError(t, tx.Rollback(ctx))
+
+	err = pgx.SetConstraints(ctx, tx, pgx.ConstraintsMode("bad"))
+	require.Error(t, err)
+
+	ensureConnValid(t, conn)
+}
+
+func TestTxRollbackTimeout(t *testing.T) {
+	t.Parallel()
+
+	config := mustParseConfig(t, os.Getenv("PGX_TEST_DATABASE"))
+	config.RollbackTimeout = 5 * time.Second
+
+	conn := mustConnect(t, config)
+	defer closeConn(t, conn)
+
+	mustExec(t, conn, "create temporary table foo(id integer)")
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tx, err := conn.Begin(context.Background())
+	require.NoError(t, err)
+	_, err = tx.Exec(context.Background(), "insert into foo(id) values (1)")
+	require.NoError(t, err)
+
+	err = tx.Commit(canceledCtx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotErrorIs(t, err, pgx.ErrTxCommitOutcomeUnknown)
+	require.False(t, conn.IsClosed())
+	require.EqualValues(t, 'I', conn.PgConn().TxStatus())
+
+	tx, err = conn.Begin(context.Background())
+	require.NoError(t, err)
+	_, err = tx.Exec(context.Background(), "insert into foo(id) values (2)")
+	require.NoError(t, err)
+
+	err = tx.Rollback(canceledCtx)
+	require.NoError(t, err)
+	require.False(t, conn.IsClosed())
+
+	var n int64
+	err = conn.QueryRow(context.Background(), "select count(*) from foo").Scan(&n)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, n)
+
+	ensureConnValid(t, conn)
+}